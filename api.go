@@ -1,11 +1,15 @@
 package twipla3as
 
 import (
+	"cmp"
 	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
-
-	"github.com/golang-jwt/jwt/v5"
+	"slices"
+	"sync"
+	"sync/atomic"
 )
 
 type Environment string
@@ -16,60 +20,200 @@ const (
 	EnvironmentProduction Environment = "production"
 )
 
+// environmentURLs holds the API and dashboard base URLs of each known TWIPLA deployment.
+var environmentURLs = map[Environment]struct {
+	api       string
+	dashboard string
+}{
+	EnvironmentDevelop: {
+		api:       "https://api-gateway.va-endpoint.com",
+		dashboard: "https://dev-dashboard-3as.va-endpoint.com/",
+	},
+	EnvironmentStage: {
+		api:       "https://stage-api-gateway.va-endpoint.com",
+		dashboard: "https://stage-dashboard-3as.va-endpoint.com/",
+	},
+	EnvironmentProduction: {
+		api:       "https://api-gateway.visitor-analytics.io",
+		dashboard: "https://app-3as.visitor-analytics.io/",
+	},
+}
+
 var (
 	ErrNoPrivateKey            = errors.New("no private key provided")
 	ErrInvalidSubscriptionType = errors.New("invalid subscription type")
 	ErrInvalidAccessToken      = errors.New("invalid access token")
+	ErrUnknownEnvironment      = errors.New("unknown environment")
+	ErrInvalidBaseURL          = errors.New("invalid base URL")
 )
 
 type TwiplaConfig struct {
 	// IntpID is the ID issued by TWIPLA for an INTP, in exchange for `jwtRS256.key.pub`
 	IntpID string
-	// PrivateKey contains the plaintext contents of the PEM file containing the INTP's private key
+	// PrivateKey contains the contents of the PEM file containing the INTP's private key, or its DER contents.
+	// RSA, ECDSA and Ed25519 keys are accepted in the PKCS#1, SEC 1 and PKCS#8 formats. See [ParsePrivateKey].
 	PrivateKey string
+	// PrivateKeyPassphrase decrypts PrivateKey, if it is encrypted.
+	PrivateKeyPassphrase string
+	// KeyID is the "kid" header of the tokens signed with PrivateKey, which identifies the key to the API gateway.
+	// If empty, IntpID is used. Distinct key IDs allow rotating keys with [TwiplaSDK.RotateKey].
+	KeyID string
+	// SigningAlgorithm is the algorithm used to sign tokens with PrivateKey.
+	// If empty, RS256 is used for RSA keys, as expected by the API gateway. See [Algorithm] for the others.
+	SigningAlgorithm Algorithm
+	// Signer signs the INTP and INTPC tokens instead of PrivateKey, such as with a key held by a KMS or an HSM.
+	// Any [crypto.Signer] can be used through [NewCryptoSigner]. A [KeyRing] allows rotating keys without downtime.
+	Signer Signer
 
 	// Environment sets which TWIPLA deployment to use. If not [EnvironmentDevelop] or [EnvironmentStage], its value is assumed to be [EnvironmentProduction]
 	Environment Environment
+	// StrictEnvironment makes [NewSDK] fail with [ErrUnknownEnvironment] when Environment is set to an unknown value,
+	// instead of assuming [EnvironmentProduction]. An empty Environment still means [EnvironmentProduction].
+	StrictEnvironment bool
+
+	// APIBaseURL overrides the base URL of the 3AS API set by Environment, such as "https://api-gateway.visitor-analytics.io".
+	// It can point to a local stand-in, an egress proxy or another region.
+	APIBaseURL string
+	// DashboardBaseURL overrides the base URL of the dashboard set by Environment, used by [TwiplaSDK.GenerateIframeURL].
+	DashboardBaseURL string
+
+	// HTTPClient is the client used for every request made to the 3AS API.
+	// It can be used to configure timeouts, proxies, TLS settings or connection pooling.
+	// To only replace the transport, use &http.Client{Transport: rt}.
+	// If nil, [http.DefaultClient] is used.
+	HTTPClient *http.Client
+
+	// Retry sets how calls failing with transient errors are retried.
+	// If nil, [DefaultRetryPolicy] is used. To disable retries, set MaxAttempts to 1.
+	Retry *RetryPolicy
+
+	// RateLimit enables a client-side rate limiter shared by all calls made through the SDK instance.
+	// If nil, calls are not limited.
+	RateLimit *RateLimit
+
+	// CircuitBreaker enables a circuit breaker, making calls fail fast with [ErrCircuitOpen] while the API is failing.
+	// If nil, no circuit breaker is used.
+	CircuitBreaker *CircuitBreaker
+
+	// IntpcTokenCacheSize is the number of signed INTPC tokens kept for reuse, such as the ones embedded by [TwiplaSDK.GenerateIframeURL].
+	// If zero, [DefaultIntpcTokenCacheSize] is used. A negative value disables INTPC token caching.
+	IntpcTokenCacheSize int
+
+	// Middleware wraps every API call made by the SDK. See [Middleware] for where it runs in the chain.
+	// More middleware can be added later with [TwiplaSDK.Use].
+	Middleware []Middleware
+
+	// Logger receives a record of every API call: method, path, status, duration and [APIError] code.
+	// Successful calls are logged at [slog.LevelDebug], failed ones at [slog.LevelWarn] along with the request body.
+	// Bearer tokens, INTPC tokens and API key secrets are always redacted.
+	// If nil, nothing is logged.
+	Logger *slog.Logger
+
+	// Tracer starts a span for every operation of the SDK, and propagates its context to the API.
+	// If nil, tracing is disabled.
+	Tracer Tracer
+
+	// Metrics receives the name, duration, HTTP status and error class of every operation of the SDK.
+	// If nil, no metrics are recorded.
+	Metrics Metrics
 }
 
 type TwiplaSDK struct {
 	signer  *tokenSigner
 	client  *http.Client
-	env     Environment
+	retry   RetryPolicy
+	limiter *rateLimiter
+	breaker *circuitBreaker
+	logger  *slog.Logger
+	tracer  Tracer
+	metrics Metrics
 	apiBase *url.URL
+	// dashboardBase is the base URL of the iframe dashboard.
+	dashboardBase *url.URL
+
+	// handler is the assembled middleware chain. It is rebuilt by [TwiplaSDK.Use].
+	handler    atomic.Pointer[Handler]
+	mu         sync.Mutex
+	middleware []Middleware
 }
 
 func NewSDK(config *TwiplaConfig) (*TwiplaSDK, error) {
-	if config.PrivateKey == "" {
-		return nil, ErrNoPrivateKey
+	key := config.Signer
+	if key == nil {
+		if config.PrivateKey == "" {
+			return nil, ErrNoPrivateKey
+		}
+		pkey, err := ParsePrivateKey([]byte(config.PrivateKey), config.PrivateKeyPassphrase)
+		if err != nil {
+			return nil, err
+		}
+		if key, err = NewCryptoSigner(pkey, cmp.Or(config.KeyID, config.IntpID), config.SigningAlgorithm); err != nil {
+			return nil, err
+		}
+	}
+	keys, ok := key.(*KeyRing)
+	if !ok {
+		keys = NewKeyRing(key)
 	}
 
-	pkey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(config.PrivateKey))
+	signer := newTokenSigner(keys, config.IntpID, config.IntpcTokenCacheSize)
+
+	urls, ok := environmentURLs[config.Environment]
+	if !ok {
+		if config.StrictEnvironment && config.Environment != "" {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownEnvironment, config.Environment)
+		}
+		config.Environment = EnvironmentProduction
+		urls = environmentURLs[EnvironmentProduction]
+	}
+
+	apiURL, err := parseBaseURL(cmp.Or(config.APIBaseURL, urls.api))
+	if err != nil {
+		return nil, err
+	}
+	dashboardURL, err := parseBaseURL(cmp.Or(config.DashboardBaseURL, urls.dashboard))
 	if err != nil {
 		return nil, err
 	}
 
-	signer := &tokenSigner{
-		privateKey: pkey,
-		intpID:     config.IntpID,
+	client := config.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
 	}
 
-	var apiPrefix string
-	switch config.Environment {
-	case EnvironmentDevelop:
-		apiPrefix = "https://api-gateway.va-endpoint.com"
-	case EnvironmentStage:
-		apiPrefix = "https://stage-api-gateway.va-endpoint.com"
-	default:
-		apiPrefix = "https://api-gateway.visitor-analytics.io"
-		config.Environment = EnvironmentProduction
+	retry := DefaultRetryPolicy
+	if config.Retry != nil {
+		retry = *config.Retry
 	}
 
-	apiURL, err := url.Parse(apiPrefix)
+	sdk := &TwiplaSDK{
+		signer:        signer,
+		client:        client,
+		retry:         retry,
+		limiter:       newRateLimiter(config.RateLimit),
+		breaker:       newCircuitBreaker(config.CircuitBreaker),
+		logger:        config.Logger,
+		tracer:        config.Tracer,
+		metrics:       config.Metrics,
+		middleware:    slices.Clone(config.Middleware),
+		apiBase:       apiURL,
+		dashboardBase: dashboardURL,
+	}
+	sdk.buildHandler()
+	return sdk, nil
+}
 
-	return &TwiplaSDK{
-		signer:  signer,
-		env:     config.Environment,
-		apiBase: apiURL,
-	}, nil
+// parseBaseURL parses an absolute http(s) URL to use as the base of requests.
+func parseBaseURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBaseURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: %q is not an absolute http(s) URL", ErrInvalidBaseURL, rawURL)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("%w: %q must not have a query or fragment", ErrInvalidBaseURL, rawURL)
+	}
+	return u, nil
 }
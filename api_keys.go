@@ -3,7 +3,6 @@ package twipla3as
 import (
 	"context"
 	"net/http"
-	"path"
 	"time"
 )
 
@@ -30,6 +29,9 @@ type CreateApiKeyArgs struct {
 }
 
 func (sdk *TwiplaSDK) CreateWebsiteApiKey(ctx context.Context, args CreateApiKeyArgs) (*ApiKey, error) {
+	if err := args.Validate(); err != nil {
+		return nil, err
+	}
 	reqBody := map[string]interface{}{
 		"name": args.Name,
 	}
@@ -41,7 +43,7 @@ func (sdk *TwiplaSDK) CreateWebsiteApiKey(ctx context.Context, args CreateApiKey
 		reqBody["expiresAt"] = args.ExpiresAt.Format(time.RFC3339)
 	}
 
-	res, err := parseResponse[ApiKey](sdk.apiCall(ctx, http.MethodPost, path.Join("/v2/3as/websites", args.ExternalWebsiteID, "api-keys"), reqBody))
+	res, err := parseResponse[ApiKey](sdk.apiCall(ctx, newOperation("CreateWebsiteApiKey", http.MethodPost, "/v2/3as/websites/{websiteId}/api-keys", args.ExternalWebsiteID), reqBody))
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +51,7 @@ func (sdk *TwiplaSDK) CreateWebsiteApiKey(ctx context.Context, args CreateApiKey
 }
 
 func (sdk *TwiplaSDK) ListWebsiteApiKeys(ctx context.Context, externalWebsiteId string) ([]ApiKey, error) {
-	res, err := parseResponse[[]ApiKey](sdk.apiCall(ctx, http.MethodGet, path.Join("/v2/3as/websites", externalWebsiteId, "api-keys"), nil))
+	res, err := parseResponse[[]ApiKey](sdk.apiCall(ctx, newOperation("ListWebsiteApiKeys", http.MethodGet, "/v2/3as/websites/{websiteId}/api-keys", externalWebsiteId), nil))
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +59,7 @@ func (sdk *TwiplaSDK) ListWebsiteApiKeys(ctx context.Context, externalWebsiteId
 }
 
 func (sdk *TwiplaSDK) DeleteWebsiteApiKey(ctx context.Context, externalWebsiteId string, apiKeyId string) error {
-	_, err := parseResponse[any](sdk.apiCall(ctx, http.MethodDelete, path.Join("/v2/3as/websites", externalWebsiteId, "api-keys", apiKeyId), nil))
+	_, err := parseResponse[any](sdk.apiCall(ctx, newOperation("DeleteWebsiteApiKey", http.MethodDelete, "/v2/3as/websites/{websiteId}/api-keys/{apiKeyId}", externalWebsiteId, apiKeyId), nil))
 
 	return err
 }
@@ -0,0 +1,95 @@
+package twipla3as_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+func TestHTTPClient(t *testing.T) {
+	var calls int
+	sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+		calls++
+		assert.Equal(t, "api-gateway.va-endpoint.com", r.URL.Host)
+		assert.Equal(t, "/v2/3as/packages/package-id", r.URL.Path)
+		assert.Contains(t, r.Header.Get("Authorization"), "Bearer ")
+		return jsonResponse(r, http.StatusOK, `{"payload":{"id":"package-id","name":"Basic"}}`), nil
+	})
+
+	pkg, err := sdk.Package(t.Context(), "package-id")
+	assert.NoError(t, err)
+	assert.Equal(t, "package-id", pkg.ID)
+	assert.Equal(t, "Basic", pkg.Name)
+	assert.Equal(t, 1, calls)
+}
+
+func TestBaseURLs(t *testing.T) {
+	t.Run("custom URLs", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/proxy/v2/3as/packages", r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"payload":[{"id":"package-id"}]}`))
+		}))
+		defer server.Close()
+
+		sdk, err := twipla3as.NewSDK(&twipla3as.TwiplaConfig{
+			IntpID:           "test-intp",
+			PrivateKey:       testPrivateKey(t),
+			APIBaseURL:       server.URL + "/proxy",
+			DashboardBaseURL: "https://dashboard.example.com/3as/",
+		})
+		assert.NoError(t, err)
+
+		packages, err := sdk.Packages(t.Context())
+		assert.NoError(t, err)
+		assert.Len(t, packages, 1)
+
+		iframeURL, err := sdk.GenerateIframeURL("intpc-id", "website-id")
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(iframeURL, "https://dashboard.example.com/3as/?externalWebsiteId=website-id&intpc_token="))
+	})
+
+	t.Run("invalid URLs", func(t *testing.T) {
+		for _, baseURL := range []string{"api-gateway.example.com", "ftp://example.com", "https://example.com/?a=b", "://"} {
+			_, err := twipla3as.NewSDK(&twipla3as.TwiplaConfig{
+				IntpID:     "test-intp",
+				PrivateKey: testPrivateKey(t),
+				APIBaseURL: baseURL,
+			})
+			assert.ErrorIs(t, err, twipla3as.ErrInvalidBaseURL, baseURL)
+		}
+	})
+
+	t.Run("environments", func(t *testing.T) {
+		for _, tc := range []struct {
+			env       twipla3as.Environment
+			strict    bool
+			dashboard string
+			err       error
+		}{
+			{env: twipla3as.EnvironmentStage, strict: true, dashboard: "https://stage-dashboard-3as.va-endpoint.com/"},
+			{env: "", strict: true, dashboard: "https://app-3as.visitor-analytics.io/"},
+			{env: "prod", strict: false, dashboard: "https://app-3as.visitor-analytics.io/"},
+			{env: "prod", strict: true, err: twipla3as.ErrUnknownEnvironment},
+		} {
+			sdk, err := twipla3as.NewSDK(&twipla3as.TwiplaConfig{
+				IntpID:            "test-intp",
+				PrivateKey:        testPrivateKey(t),
+				Environment:       tc.env,
+				StrictEnvironment: tc.strict,
+			})
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				continue
+			}
+			assert.NoError(t, err)
+			iframeURL, err := sdk.GenerateIframeURL("intpc-id", "website-id")
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(iframeURL, tc.dashboard+"?"), iframeURL)
+		}
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 )
 
 type APIError struct {
@@ -17,14 +18,51 @@ type APIError struct {
 	Message    string `json:"message"`
 	Code       int    `json:"code"`
 	OtherError string `json:"error"`
+	// Fields holds the per-field validation details of the error, when the API returns them.
+	Fields []FieldError `json:"-"`
+
+	// Meta holds the metadata of the error response, such as the request ID to quote in support tickets.
+	Meta *ResponseMeta `json:"-"`
 }
 
 func (e APIError) Error() string {
-	return fmt.Sprintf("API error: %d %s (Code: %d)", e.Status, e.Message, e.Code)
+	msg := fmt.Sprintf("API error: %d %s (Code: %d)", e.Status, e.Message, e.Code)
+	if e.OtherError != "" {
+		msg += " (Error: " + e.OtherError + ")"
+	}
+	if e.Meta != nil && e.Meta.RequestID != "" {
+		msg += " (Request ID: " + e.Meta.RequestID + ")"
+	}
+	return msg
 }
 
-func (sdk *TwiplaSDK) apiCall(ctx context.Context, method string, path string, body any) (*http.Response, error) {
-	var r *http.Request
+func (sdk *TwiplaSDK) apiCall(ctx context.Context, op operation, body any) (resp *http.Response, err error) {
+	path, err := op.path()
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	ctx, span := sdk.startSpan(ctx, op)
+	defer func() {
+		endSpan(span, resp, err)
+		sdk.observeCall(op, start, resp, err)
+	}()
+
+	options := callOptionsFrom(ctx)
+	if options.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.timeout)
+		defer func() {
+			// The deadline must keep applying while the caller reads the response body.
+			if err != nil || resp == nil {
+				cancel()
+			} else {
+				resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			}
+		}()
+	}
+
 	query, ok := body.(url.Values)
 	if !ok {
 		query = nil
@@ -37,57 +75,92 @@ func (sdk *TwiplaSDK) apiCall(ctx context.Context, method string, path string, b
 		finalPath.RawQuery = query.Encode()
 	}
 
+	var reader io.Reader
 	if body != nil {
 		data, err := json.Marshal(body)
 		if err != nil {
 			return nil, err
 		}
-		r, err = http.NewRequestWithContext(ctx, method, finalPath.String(), bytes.NewReader(data))
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		var err error
-		r, err = http.NewRequestWithContext(ctx, method, finalPath.String(), nil)
-		if err != nil {
-			return nil, err
-		}
+		reader = bytes.NewReader(data)
 	}
 
-	token, err := sdk.signer.IntpToken()
+	state := &callState{options: options}
+	r, err := http.NewRequestWithContext(context.WithValue(ctx, callStateKey{}, state), op.method, finalPath.String(), reader)
 	if err != nil {
-		return nil, fmt.Errorf("can't sign bearer intp token: %w", err)
+		return nil, err
 	}
-	r.Header.Set("Authorization", "Bearer "+token)
-	if !(method == http.MethodGet || method == http.MethodDelete) {
+	if !(op.method == http.MethodGet || op.method == http.MethodDelete) {
 		r.Header.Set("Content-Type", "application/json")
 	}
+	if sdk.tracer != nil {
+		sdk.tracer.Inject(ctx, r.Header)
+	}
+	if state.idempotent, err = setIdempotencyKey(ctx, r); err != nil {
+		return nil, err
+	}
+	for key, values := range options.header {
+		r.Header[key] = append(r.Header[key], values...)
+	}
 
-	resp, err := http.DefaultClient.Do(r)
+	resp, err = (*sdk.handler.Load())(r)
+	captureResponse(options.response, state.response)
+	if options.meta != nil && state.response != nil {
+		*options.meta = *newResponseMeta(state.response)
+	}
+	if err != nil && state.attempts > 1 {
+		return nil, &RetryError{Attempts: state.attempts, Err: err}
+	}
 	if err != nil {
 		return nil, err
 	}
+	return resp, nil
+}
 
-	if resp.StatusCode >= 400 {
-		defer resp.Body.Close()
-		if !strings.Contains(resp.Header.Get("Content-Type"), "json") {
-			data, err := io.ReadAll(resp.Body)
-			if err != nil {
-				return nil, err
-			}
-			return nil, fmt.Errorf("non-json error response: %q", string(data))
-		}
-		var apiError APIError
-		if err := json.NewDecoder(resp.Body).Decode(&apiError); err != nil {
-			return nil, err
-		}
-		if apiError.OtherError == "invalid access token" {
-			return nil, ErrInvalidAccessToken
-		}
-		return nil, apiError
-	}
+// callState holds the information gathered by the middleware chain during an API call.
+type callState struct {
+	options callOptions
+	// response is the last response received, before it is decoded.
+	response *http.Response
+	// attempts is the number of times the request was sent.
+	attempts int
+	// idempotent is set when the caller supplied an idempotency key, allowing the request to be retried regardless of its method.
+	idempotent bool
+	// keyLevel is the level in the SDK's [KeyRing] of the key that signed the last INTP token sent.
+	keyLevel int
+}
 
-	return resp, nil
+type callStateKey struct{}
+
+func callStateFrom(ctx context.Context) *callState {
+	state, _ := ctx.Value(callStateKey{}).(*callState)
+	return state
+}
+
+// decodeError consumes and closes the body of an error response, and returns the error it describes.
+// It returns nil for successful responses.
+func decodeError(resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+	defer resp.Body.Close()
+	isJSON := strings.Contains(resp.Header.Get("Content-Type"), "json")
+	limit := int64(maxHTTPErrorBody)
+	if isJSON {
+		limit = maxAPIErrorBody
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return err
+	}
+	var apiError APIError
+	if !isJSON || json.Unmarshal(data, &apiError) != nil {
+		return newHTTPError(resp, data)
+	}
+	if apiError.Status == 0 {
+		apiError.Status = resp.StatusCode
+	}
+	apiError.Meta = newResponseMeta(resp)
+	return apiError
 }
 
 // PaginationMetadata is returned for paginated responses and shows metadata about the number of items that can be returned by the endpoint, as well as information about the current page.
@@ -119,16 +192,12 @@ func parseResponse[T any](w *http.Response, err error) (twiplaResponse[T], error
 	}
 
 	contentType, _, err := mime.ParseMediaType(w.Header.Get("Content-Type"))
-	if err != nil {
-		return t, fmt.Errorf("error parsing content type: %w", err)
-	}
-	if contentType != "application/json" {
-		data, err := io.ReadAll(w.Body)
-		defer w.Body.Close()
+	if err != nil || contentType != "application/json" {
+		data, err := io.ReadAll(io.LimitReader(w.Body, maxHTTPErrorBody+1))
 		if err != nil {
 			return t, err
 		}
-		return t, fmt.Errorf("unexpected content type: %s, body: %q", w.Header.Get("Content-Type"), string(data))
+		return t, newHTTPError(w, data)
 	}
 	if err := json.NewDecoder(w.Body).Decode(&t); err != nil {
 		return t, err
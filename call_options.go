@@ -0,0 +1,125 @@
+package twipla3as
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// CallOption adjusts a single call made by the SDK.
+// Call options are passed to the SDK methods through their context, with [WithCallOptions]:
+//
+//	ctx = twipla3as.WithCallOptions(ctx, twipla3as.CallTimeout(time.Minute), twipla3as.CallNoRetry())
+//	intpc, err := sdk.DeleteINTPC(ctx, intpcID)
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	timeout      time.Duration
+	header       http.Header
+	retry        *RetryPolicy
+	noTokenCache bool
+	response     *http.Response
+	meta         *ResponseMeta
+}
+
+type callOptionsKey struct{}
+
+// WithCallOptions returns a context applying opts to the SDK calls it is passed to,
+// after the call options already carried by ctx.
+func WithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	existing, _ := ctx.Value(callOptionsKey{}).([]CallOption)
+	return context.WithValue(ctx, callOptionsKey{}, append(slices.Clip(existing), opts...))
+}
+
+func callOptionsFrom(ctx context.Context) callOptions {
+	var options callOptions
+	opts, _ := ctx.Value(callOptionsKey{}).([]CallOption)
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// CallTimeout sets a deadline for the whole call, including retries and reading the response.
+func CallTimeout(d time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = d
+	}
+}
+
+// CallHeader adds a header to the requests sent by the call.
+func CallHeader(key string, value string) CallOption {
+	return func(o *callOptions) {
+		if o.header == nil {
+			o.header = http.Header{}
+		}
+		o.header.Add(key, value)
+	}
+}
+
+// CallRetry replaces the SDK's retry policy for the call.
+func CallRetry(policy RetryPolicy) CallOption {
+	return func(o *callOptions) {
+		o.retry = &policy
+	}
+}
+
+// CallNoRetry disables retries for the call.
+func CallNoRetry() CallOption {
+	return CallRetry(RetryPolicy{MaxAttempts: 1})
+}
+
+// CallNoCache makes the call sign a fresh INTP token instead of reusing the cached one.
+func CallNoCache() CallOption {
+	return func(o *callOptions) {
+		o.noTokenCache = true
+	}
+}
+
+// CaptureResponse stores the metadata (status, headers, protocol) of the last response received by the call in dst,
+// including error responses. The body of the captured response is always empty,
+// and the credentials of its Request, such as the INTP bearer token, are redacted.
+// dst is left untouched if no response was received.
+func CaptureResponse(dst *http.Response) CallOption {
+	return func(o *callOptions) {
+		o.response = dst
+	}
+}
+
+// CaptureMeta stores the metadata of the last response received by the call in dst, including error responses.
+// dst is left untouched if no response was received.
+func CaptureMeta(dst *ResponseMeta) CallOption {
+	return func(o *callOptions) {
+		o.meta = dst
+	}
+}
+
+// captureResponse copies the metadata of resp to dst.
+func captureResponse(dst *http.Response, resp *http.Response) {
+	if dst == nil || resp == nil {
+		return
+	}
+	*dst = *resp
+	dst.Body = http.NoBody
+	dst.Header = resp.Header.Clone()
+	dst.Trailer = resp.Trailer.Clone()
+	if resp.Request != nil {
+		dst.Request = resp.Request.Clone(context.Background())
+		dst.Request.Header = redactHeader(resp.Request.Header)
+		dst.Request.Body = http.NoBody
+		dst.Request.GetBody = nil
+	}
+}
+
+// cancelOnClose releases the resources of a call's timeout once its response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
@@ -0,0 +1,66 @@
+package twipla3as_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+func TestCallOptions(t *testing.T) {
+	t.Run("timeout", func(t *testing.T) {
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			if r.Method == http.MethodDelete {
+				<-r.Context().Done()
+				return nil, r.Context().Err()
+			}
+			return jsonResponse(r, http.StatusOK, `{"payload":{"id":"package-id"}}`), nil
+		})
+
+		ctx := twipla3as.WithCallOptions(t.Context(), twipla3as.CallTimeout(20*time.Millisecond))
+		_, err := sdk.DeleteINTPC(ctx, "intpc-id")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		pkg, err := sdk.Package(ctx, "package-id")
+		assert.NoError(t, err)
+		assert.Equal(t, "package-id", pkg.ID)
+	})
+
+	t.Run("headers and retries", func(t *testing.T) {
+		var calls int
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			calls++
+			assert.Equal(t, []string{"a", "b"}, r.Header.Values("X-Tag"))
+			return jsonResponse(r, http.StatusBadGateway, `{"status":502,"message":"bad gateway"}`), nil
+		}, fastRetries(3, false))
+
+		ctx := twipla3as.WithCallOptions(t.Context(), twipla3as.CallHeader("X-Tag", "a"))
+		ctx = twipla3as.WithCallOptions(ctx, twipla3as.CallHeader("X-Tag", "b"), twipla3as.CallNoRetry())
+		_, err := sdk.Packages(ctx)
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("response capture", func(t *testing.T) {
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			resp := jsonResponse(r, http.StatusNotFound, `{"status":404,"message":"not found"}`)
+			resp.Header.Set("X-Request-Id", "request-id")
+			return resp, nil
+		})
+
+		var resp http.Response
+		ctx := twipla3as.WithCallOptions(t.Context(), twipla3as.CaptureResponse(&resp))
+		_, err := sdk.Website(ctx, "website-id")
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, "request-id", resp.Header.Get("X-Request-Id"))
+		assert.Equal(t, http.NoBody, resp.Body)
+		if assert.NotNil(t, resp.Request) {
+			assert.Equal(t, "Bearer [REDACTED]", resp.Request.Header.Get("Authorization"))
+			assert.Equal(t, "/v2/3as/websites/website-id", resp.Request.URL.Path)
+		}
+	})
+}
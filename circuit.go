@@ -0,0 +1,211 @@
+package twipla3as
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending the request while the circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreaker configures a circuit breaker guarding the requests sent to the 3AS API.
+//
+// Network errors, timeouts and 5xx responses count as failures. When the ratio of failed requests within Window
+// reaches FailureRatio, the circuit opens and requests fail fast with [ErrCircuitOpen].
+// After OpenTimeout, the circuit is half-open and lets a few probe requests through:
+// it closes again once HalfOpenRequests of them succeeded, and opens again as soon as one fails.
+type CircuitBreaker struct {
+	// FailureRatio is the ratio of failed requests, between 0 and 1, at or above which the circuit opens. It defaults to 0.5.
+	FailureRatio float64
+	// MinRequests is the number of requests needed within Window before the circuit can open. It defaults to 10.
+	MinRequests int
+	// Window is the period over which failures are counted. It defaults to one minute.
+	Window time.Duration
+	// OpenTimeout is how long the circuit stays open before probing the API again. It defaults to 30 seconds.
+	OpenTimeout time.Duration
+	// HalfOpenRequests is the number of successful probe requests needed to close the circuit. It defaults to 1.
+	HalfOpenRequests int
+	// OnStateChange, if set, is called whenever the circuit changes state. It must not block.
+	// It is called without holding the breaker's lock, so it may call [TwiplaSDK.CircuitState].
+	OnStateChange func(from, to CircuitState)
+}
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets all requests through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects all requests with [ErrCircuitOpen].
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe requests through.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitState returns the current state of the circuit breaker, so that health checks can report the API as degraded.
+// It is always [CircuitClosed] if no circuit breaker is configured.
+func (sdk *TwiplaSDK) CircuitState() CircuitState {
+	return sdk.breaker.currentState()
+}
+
+type circuitBreaker struct {
+	config CircuitBreaker
+
+	mu    sync.Mutex
+	state CircuitState
+	// windowStart, requests and failures count the requests of the current window, while closed.
+	windowStart time.Time
+	requests    int
+	failures    int
+	// openedAt is when the circuit last opened.
+	openedAt time.Time
+	// probes and successes count the in-flight and successful probe requests, while half-open.
+	probes    int
+	successes int
+	// transitions holds the state changes to report to OnStateChange once b.mu is released.
+	transitions []circuitTransition
+}
+
+type circuitTransition struct {
+	from, to CircuitState
+}
+
+func newCircuitBreaker(config *CircuitBreaker) *circuitBreaker {
+	if config == nil {
+		return nil
+	}
+	c := *config
+	c.FailureRatio = cmp.Or(c.FailureRatio, 0.5)
+	c.MinRequests = cmp.Or(c.MinRequests, 10)
+	c.Window = cmp.Or(c.Window, time.Minute)
+	c.OpenTimeout = cmp.Or(c.OpenTimeout, 30*time.Second)
+	c.HalfOpenRequests = cmp.Or(c.HalfOpenRequests, 1)
+	return &circuitBreaker{config: c, windowStart: time.Now()}
+}
+
+func (b *circuitBreaker) currentState() CircuitState {
+	if b == nil {
+		return CircuitClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.config.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// allow reports whether a request can be sent, and whether it is a probe request.
+func (b *circuitBreaker) allow() (probe bool, err error) {
+	b.mu.Lock()
+	defer b.unlock()
+	if b.state == CircuitOpen {
+		if time.Since(b.openedAt) < b.config.OpenTimeout {
+			return false, ErrCircuitOpen
+		}
+		b.setState(CircuitHalfOpen)
+	}
+	if b.state == CircuitHalfOpen {
+		if b.probes >= b.config.HalfOpenRequests {
+			return false, ErrCircuitOpen
+		}
+		b.probes++
+		return true, nil
+	}
+	return false, nil
+}
+
+// record updates the circuit with the outcome of a request allowed by allow.
+func (b *circuitBreaker) record(probe bool, failed bool) {
+	b.mu.Lock()
+	defer b.unlock()
+	now := time.Now()
+	if probe {
+		b.probes--
+		if b.state != CircuitHalfOpen {
+			return
+		}
+		if failed {
+			b.open(now)
+		} else if b.successes++; b.successes >= b.config.HalfOpenRequests {
+			b.setState(CircuitClosed)
+			b.windowStart, b.requests, b.failures = now, 0, 0
+		}
+		return
+	}
+
+	if b.state != CircuitClosed {
+		return
+	}
+	if now.Sub(b.windowStart) > b.config.Window {
+		b.windowStart, b.requests, b.failures = now, 0, 0
+	}
+	b.requests++
+	if failed {
+		b.failures++
+	}
+	if b.requests >= b.config.MinRequests && float64(b.failures)/float64(b.requests) >= b.config.FailureRatio {
+		b.open(now)
+	}
+}
+
+// open opens the circuit. b.mu must be held.
+func (b *circuitBreaker) open(now time.Time) {
+	b.openedAt = now
+	b.setState(CircuitOpen)
+}
+
+// setState changes the state of the circuit. b.mu must be held.
+func (b *circuitBreaker) setState(state CircuitState) {
+	from := b.state
+	if from == state {
+		return
+	}
+	b.state = state
+	b.probes, b.successes = 0, 0
+	if b.config.OnStateChange != nil {
+		b.transitions = append(b.transitions, circuitTransition{from: from, to: state})
+	}
+}
+
+// unlock releases b.mu, then reports the state changes made while it was held.
+func (b *circuitBreaker) unlock() {
+	transitions := b.transitions
+	b.transitions = nil
+	b.mu.Unlock()
+	for _, t := range transitions {
+		b.config.OnStateChange(t.from, t.to)
+	}
+}
+
+// circuitBroken fails fast while the circuit breaker is open, and records the outcome of the requests it lets through.
+// It runs inside the rate limiter, so that only the outcome of requests actually sent is recorded.
+func (sdk *TwiplaSDK) circuitBroken(next Handler) Handler {
+	return func(r *http.Request) (*http.Response, error) {
+		probe, err := sdk.breaker.allow()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := next(r)
+		failed := (err != nil && !errors.Is(err, context.Canceled)) || (resp != nil && resp.StatusCode >= 500)
+		sdk.breaker.record(probe, failed)
+		return resp, err
+	}
+}
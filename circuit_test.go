@@ -0,0 +1,88 @@
+package twipla3as_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var calls int
+	healthy := false
+	var transitions []string
+	var sdk *twipla3as.TwiplaSDK
+	sdk = newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+		calls++
+		if healthy {
+			return jsonResponse(r, http.StatusOK, `{"payload":[]}`), nil
+		}
+		return jsonResponse(r, http.StatusServiceUnavailable, `{"status":503,"message":"unavailable"}`), nil
+	}, fastRetries(1, false), func(c *twipla3as.TwiplaConfig) {
+		c.CircuitBreaker = &twipla3as.CircuitBreaker{
+			FailureRatio: 0.5,
+			MinRequests:  4,
+			OpenTimeout:  50 * time.Millisecond,
+			OnStateChange: func(from, to twipla3as.CircuitState) {
+				// Health checks may read the state from the callback.
+				sdk.CircuitState()
+				transitions = append(transitions, from.String()+"->"+to.String())
+			},
+		}
+	})
+
+	for range 4 {
+		_, err := sdk.Packages(t.Context())
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, twipla3as.ErrCircuitOpen)
+	}
+	assert.Equal(t, twipla3as.CircuitOpen, sdk.CircuitState())
+
+	_, err := sdk.Packages(t.Context())
+	assert.ErrorIs(t, err, twipla3as.ErrCircuitOpen)
+	assert.Equal(t, 4, calls)
+
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, twipla3as.CircuitHalfOpen, sdk.CircuitState())
+	_, err = sdk.Packages(t.Context())
+	assert.Error(t, err)
+	assert.Equal(t, twipla3as.CircuitOpen, sdk.CircuitState())
+
+	time.Sleep(60 * time.Millisecond)
+	healthy = true
+	_, err = sdk.Packages(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, twipla3as.CircuitClosed, sdk.CircuitState())
+	assert.Equal(t, 6, calls)
+
+	assert.Equal(t, []string{
+		"closed->open",
+		"open->half-open",
+		"half-open->open",
+		"open->half-open",
+		"half-open->closed",
+	}, transitions)
+}
+
+func TestCircuitBreakerRateLimited(t *testing.T) {
+	var calls int
+	sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+		calls++
+		return jsonResponse(r, http.StatusOK, `{"payload":[]}`), nil
+	}, fastRetries(1, false), func(c *twipla3as.TwiplaConfig) {
+		c.RateLimit = &twipla3as.RateLimit{ReadsPerSecond: 1, ReadBurst: 1}
+		c.CircuitBreaker = &twipla3as.CircuitBreaker{MinRequests: 2}
+	})
+
+	// Requests whose deadline expires while waiting for the rate limiter are never sent, and are not failures.
+	for range 4 {
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		_, _ = sdk.Packages(ctx)
+		cancel()
+	}
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, twipla3as.CircuitClosed, sdk.CircuitState())
+}
@@ -1,6 +1,7 @@
 package twipla3as
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 )
@@ -8,19 +9,7 @@ import (
 // GenerateIframeURL generates a URL that can be used to embed the 3as dashboard in an iframe.
 // intpcID and websiteID are the INTP's internal IDs for the customer and the website.
 func (sdk *TwiplaSDK) GenerateIframeURL(intpcID string, websiteID string) (string, error) {
-	var baseURL string
-	switch sdk.env {
-	case EnvironmentDevelop:
-		baseURL = "https://dev-dashboard-3as.va-endpoint.com/"
-	case EnvironmentStage:
-		baseURL = "https://stage-dashboard-3as.va-endpoint.com/"
-	case EnvironmentProduction:
-		baseURL = "https://app-3as.visitor-analytics.io/"
-	default:
-		return "", fmt.Errorf("unsupported env: %s", sdk.env)
-	}
-
-	token, err := sdk.signer.IntpcToken(intpcID)
+	token, err := sdk.signer.IntpcToken(context.Background(), intpcID)
 	if err != nil {
 		return "", fmt.Errorf("could not generate intpc token: %w", err)
 	}
@@ -28,5 +17,9 @@ func (sdk *TwiplaSDK) GenerateIframeURL(intpcID string, websiteID string) (strin
 	query.Set("intpc_token", token)
 	query.Set("externalWebsiteId", websiteID)
 
-	return baseURL + "?" + query.Encode(), nil
+	u := *sdk.dashboardBase
+	u.RawQuery = query.Encode()
+	iframeURL := u.String()
+	sdk.logIframeURL(intpcID, iframeURL)
+	return iframeURL, nil
 }
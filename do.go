@@ -0,0 +1,58 @@
+package twipla3as
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidPath is returned by [TwiplaSDK.Do] and [TwiplaSDK.DoPaginated] for paths that are not absolute API paths.
+var ErrInvalidPath = errors.New("invalid API path")
+
+// Do calls an arbitrary endpoint of the 3AS API, such as one not wrapped by the SDK yet.
+// path is the escaped path of the endpoint, such as "/v2/3as/websites/my-website/whitelisted-domains".
+//
+// body is sent as JSON, unless it is a [url.Values], which is sent as the query string instead.
+// The payload of the response is decoded into out, unless out is nil.
+// The call goes through the same signing, middleware, retries and error decoding as the other SDK methods.
+// Since path may contain IDs, it is traced with the "/{path}" route rather than as is.
+func (sdk *TwiplaSDK) Do(ctx context.Context, method string, path string, body any, out any) error {
+	_, err := sdk.do(ctx, method, path, body, out)
+	return err
+}
+
+// DoPaginated calls an arbitrary paginated GET endpoint of the 3AS API, and decodes the payload of the requested page into out.
+// query holds extra query parameters, such as filters, and may be nil.
+func (sdk *TwiplaSDK) DoPaginated(ctx context.Context, path string, query url.Values, pagination Pagination, out any) (PaginationMetadata, error) {
+	pageQuery := pagination.buildQuery()
+	for key, values := range query {
+		pageQuery[key] = values
+	}
+	return sdk.do(ctx, http.MethodGet, path, pageQuery, out)
+}
+
+func (sdk *TwiplaSDK) do(ctx context.Context, method string, path string, body any, out any) (PaginationMetadata, error) {
+	if !strings.HasPrefix(path, "/") || strings.ContainsAny(path, "?#") {
+		return PaginationMetadata{}, fmt.Errorf("%w: %q", ErrInvalidPath, path)
+	}
+	for segment := range strings.SplitSeq(path, "/") {
+		if segment == "." || segment == ".." {
+			return PaginationMetadata{}, fmt.Errorf("%w: %q", ErrInvalidPath, path)
+		}
+	}
+
+	resp, err := parseResponse[json.RawMessage](sdk.apiCall(ctx, newRawOperation("Do", method, path), body))
+	if err != nil {
+		return PaginationMetadata{}, err
+	}
+	if out != nil && len(resp.Payload) > 0 {
+		if err := json.Unmarshal(resp.Payload, out); err != nil {
+			return PaginationMetadata{}, fmt.Errorf("can't decode payload: %w", err)
+		}
+	}
+	return resp.Metadata, nil
+}
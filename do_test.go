@@ -0,0 +1,65 @@
+package twipla3as_test
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+func TestDo(t *testing.T) {
+	tracer := &testTracer{}
+	sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+		assert.Contains(t, r.Header.Get("Authorization"), "Bearer ")
+		switch r.URL.Path {
+		case "/v3/3as/websites/website-id/reports":
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.JSONEq(t, `{"from":"2025-01-01"}`, string(body))
+			return jsonResponse(r, http.StatusOK, `{"payload":{"visits":42}}`), nil
+		case "/v2/3as/websites":
+			assert.Equal(t, "2", r.URL.Query().Get("page"))
+			assert.Equal(t, "5", r.URL.Query().Get("pageSize"))
+			assert.Equal(t, "intpc-id", r.URL.Query().Get("externalCustomerId"))
+			return jsonResponse(r, http.StatusOK, `{"payload":[{"id":"website-uuid"}],"meta":{"page":2,"pageSize":5,"pageTotal":3,"total":11}}`), nil
+		default:
+			return jsonResponse(r, http.StatusNotFound, `{"status":404,"message":"not found","code":7}`), nil
+		}
+	}, func(c *twipla3as.TwiplaConfig) {
+		c.Tracer = tracer
+	})
+
+	t.Run("Do", func(t *testing.T) {
+		var report struct {
+			Visits int `json:"visits"`
+		}
+		err := sdk.Do(t.Context(), http.MethodPost, "/v3/3as/websites/website-id/reports", map[string]string{"from": "2025-01-01"}, &report)
+		assert.NoError(t, err)
+		assert.Equal(t, 42, report.Visits)
+		if assert.NotEmpty(t, tracer.spans) {
+			assert.Equal(t, "/{path}", tracer.spans[len(tracer.spans)-1].attrs[twipla3as.AttributeHTTPRoute])
+		}
+
+		err = sdk.Do(t.Context(), http.MethodGet, "/v3/3as/unknown", nil, nil)
+		var apiErr twipla3as.APIError
+		assert.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, 7, apiErr.Code)
+	})
+
+	t.Run("DoPaginated", func(t *testing.T) {
+		var websites []twipla3as.Website
+		meta, err := sdk.DoPaginated(t.Context(), "/v2/3as/websites", url.Values{"externalCustomerId": {"intpc-id"}}, twipla3as.Pagination{Page: 2, PageSize: 5}, &websites)
+		assert.NoError(t, err)
+		assert.Equal(t, twipla3as.PaginationMetadata{Page: 2, PageSize: 5, PageTotal: 3, Total: 11}, meta)
+		assert.Len(t, websites, 1)
+	})
+
+	t.Run("invalid paths", func(t *testing.T) {
+		for _, path := range []string{"v2/3as/websites", "/v2/3as/websites?page=1", "/v2/3as/../admin"} {
+			assert.ErrorIs(t, sdk.Do(t.Context(), http.MethodGet, path, nil, nil), twipla3as.ErrInvalidPath)
+		}
+	})
+}
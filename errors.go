@@ -0,0 +1,133 @@
+package twipla3as
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// Sentinel errors matched by [APIError] and [HTTPError] with [errors.Is], derived from their status and message.
+// [errors.As] still gives access to the full error.
+var (
+	// ErrNotFound is matched by 404 errors.
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists is matched by 409 errors.
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrUnauthorized is matched by 401 errors, including [ErrInvalidAccessToken].
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden is matched by 403 errors.
+	ErrForbidden = errors.New("forbidden")
+	// ErrRateLimited is matched by 429 errors.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrValidation is matched by 400 and 422 errors, and errors with [APIError.Fields], returned for invalid arguments.
+	ErrValidation = errors.New("validation failed")
+	// ErrServerError is matched by 5xx errors.
+	ErrServerError = errors.New("server error")
+)
+
+// Is reports whether e matches target, one of the sentinel errors such as [ErrNotFound] or [ErrInvalidAccessToken].
+func (e APIError) Is(target error) bool {
+	switch target {
+	case ErrInvalidAccessToken:
+		return e.invalidAccessToken()
+	case ErrUnauthorized:
+		return e.Status == http.StatusUnauthorized || e.invalidAccessToken()
+	case ErrValidation:
+		return (statusIs(e.Status, target) || len(e.Fields) > 0) && e.Status != http.StatusConflict
+	}
+	// The API does not document the codes of its errors, so they are matched on their status alone.
+	return statusIs(e.Status, target)
+}
+
+func (e APIError) invalidAccessToken() bool {
+	return e.OtherError == "invalid access token"
+}
+
+// statusIs reports whether an error with the given HTTP status matches target, based on the status alone.
+func statusIs(status int, target error) bool {
+	switch target {
+	case ErrNotFound:
+		return status == http.StatusNotFound
+	case ErrAlreadyExists:
+		return status == http.StatusConflict
+	case ErrUnauthorized:
+		return status == http.StatusUnauthorized
+	case ErrForbidden:
+		return status == http.StatusForbidden
+	case ErrRateLimited:
+		return status == http.StatusTooManyRequests
+	case ErrValidation:
+		return status == http.StatusBadRequest || status == http.StatusUnprocessableEntity
+	case ErrServerError:
+		return status >= 500
+	}
+	return false
+}
+
+// IsTemporary reports whether err was caused by a transient condition of the network or the API,
+// such as a network timeout including [http.Client.Timeout], a connection reset, an open circuit breaker,
+// a 429 response or a 502, 503 or 504 response.
+// Other transport errors, such as TLS certificate verification failures, are permanent.
+//
+// A request timing out because of the deadline of the caller's context is temporary as well,
+// but the SDK does not retry it, since the context is done.
+func IsTemporary(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, ErrCircuitOpen) || transientNetworkError(err) {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return temporaryStatus(errorStatus(nil, err))
+}
+
+// transientNetworkError reports whether err is a network timeout, or a connection that was refused or dropped.
+// The bare [context.DeadlineExceeded], which is not a network error, is not one.
+func transientNetworkError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() && netErr != context.DeadlineExceeded {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// IsRetryable reports whether a later attempt of the call that failed with err may succeed, for example to requeue a job.
+// It is true for temporary errors (see [IsTemporary]), and for calls that ran out of time.
+// Calls canceled by the caller, client errors and other server errors are permanent.
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	return errors.Is(err, context.DeadlineExceeded) || IsTemporary(err)
+}
+
+// IsClientError reports whether the API rejected the call itself, with a 4xx response other than 429,
+// or whether the SDK rejected its arguments with a [ValidationError] before sending it.
+// Such calls fail again if retried unchanged.
+func IsClientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return true
+	}
+	status := errorStatus(nil, err)
+	return status >= 400 && status < 500 && status != http.StatusTooManyRequests
+}
+
+// temporaryStatus reports whether a response with the given status is worth retrying.
+func temporaryStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,157 @@
+package twipla3as_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	sentinels := []error{
+		twipla3as.ErrNotFound,
+		twipla3as.ErrAlreadyExists,
+		twipla3as.ErrUnauthorized,
+		twipla3as.ErrForbidden,
+		twipla3as.ErrRateLimited,
+		twipla3as.ErrValidation,
+		twipla3as.ErrServerError,
+	}
+	for _, tc := range []struct {
+		status int
+		body   string
+		want   error
+	}{
+		{status: http.StatusNotFound, body: `{"status":404,"message":"intpc not found"}`, want: twipla3as.ErrNotFound},
+		{status: http.StatusConflict, body: `{"status":409,"message":"conflict"}`, want: twipla3as.ErrAlreadyExists},
+		{status: http.StatusBadRequest, body: `{"status":400,"message":"Website already exists"}`, want: twipla3as.ErrValidation},
+		{status: http.StatusUnauthorized, body: `{"status":401,"message":"unauthorized"}`, want: twipla3as.ErrUnauthorized},
+		{status: http.StatusForbidden, body: `{"status":403,"message":"forbidden"}`, want: twipla3as.ErrForbidden},
+		{status: http.StatusUnprocessableEntity, body: `{"status":422,"message":"invalid email"}`, want: twipla3as.ErrValidation},
+		{status: http.StatusInternalServerError, body: `{"status":500,"message":"boom"}`, want: twipla3as.ErrServerError},
+	} {
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(r, tc.status, tc.body), nil
+		}, fastRetries(1, false))
+
+		_, err := sdk.INTPC(t.Context(), "intpc-id")
+		for _, sentinel := range sentinels {
+			assert.Equal(t, sentinel == tc.want, errors.Is(err, sentinel), "%s matching %v", tc.body, sentinel)
+		}
+		var apiErr twipla3as.APIError
+		if assert.ErrorAs(t, err, &apiErr) {
+			assert.Equal(t, tc.status, apiErr.Status)
+		}
+	}
+
+	t.Run("invalid access token", func(t *testing.T) {
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(r, http.StatusUnauthorized, `{"status":401,"error":"invalid access token"}`), nil
+		})
+
+		_, err := sdk.INTPC(t.Context(), "intpc-id")
+		assert.ErrorIs(t, err, twipla3as.ErrInvalidAccessToken)
+		assert.ErrorIs(t, err, twipla3as.ErrUnauthorized)
+		var apiErr twipla3as.APIError
+		if assert.ErrorAs(t, err, &apiErr) {
+			assert.Equal(t, apiErr, err, "the APIError itself matches ErrInvalidAccessToken")
+		}
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(r, http.StatusTooManyRequests, `{"status":429,"message":"slow down"}`), nil
+		}, fastRetries(2, false))
+
+		_, err := sdk.INTPC(t.Context(), "intpc-id")
+		assert.ErrorIs(t, err, twipla3as.ErrRateLimited)
+		assert.NotErrorIs(t, err, twipla3as.ErrServerError)
+	})
+}
+
+func TestErrorClassification(t *testing.T) {
+	respond := func(resp func(r *http.Request) *http.Response, err error) error {
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			if err != nil {
+				return nil, err
+			}
+			return resp(r), nil
+		}, fastRetries(1, false))
+		_, callErr := sdk.INTPC(t.Context(), "intpc-id")
+		return callErr
+	}
+	status := func(status int, body string) error {
+		return respond(func(r *http.Request) *http.Response { return jsonResponse(r, status, body) }, nil)
+	}
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+	var attempts int
+	_, clientTimeout := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+		attempts++
+		return http.DefaultTransport.RoundTrip(r)
+	}, fastRetries(2, false), func(c *twipla3as.TwiplaConfig) {
+		c.HTTPClient.Timeout = 50 * time.Millisecond
+		c.APIBaseURL = slow.URL
+	}).Packages(t.Context())
+	assert.Equal(t, 2, attempts, "client timeouts are retried")
+
+	_, invalidArgs := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+		t.Error("invalid arguments must not be sent")
+		return nil, nil
+	}).INTPC(t.Context(), "")
+
+	for _, tc := range []struct {
+		name                          string
+		err                           error
+		temporary, retryable, invalid bool
+	}{
+		{name: "network", err: respond(nil, syscall.ECONNRESET), temporary: true, retryable: true},
+		{name: "connection refused", err: respond(nil, syscall.ECONNREFUSED), temporary: true, retryable: true},
+		{name: "truncated response", err: respond(nil, io.ErrUnexpectedEOF), temporary: true, retryable: true},
+		{name: "network timeout", err: respond(nil, timeoutError{}), temporary: true, retryable: true},
+		{name: "client timeout", err: clientTimeout, temporary: true, retryable: true},
+		{name: "untrusted certificate", err: respond(nil, &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}})},
+		{name: "rate limited", err: status(http.StatusTooManyRequests, `{"status":429}`), temporary: true, retryable: true},
+		{name: "unavailable", err: status(http.StatusServiceUnavailable, `{"status":503}`), temporary: true, retryable: true},
+		{name: "non-JSON gateway error", err: respond(func(r *http.Request) *http.Response {
+			resp := jsonResponse(r, http.StatusBadGateway, "<html></html>")
+			resp.Header.Set("Content-Type", "text/html")
+			return resp
+		}, nil), temporary: true, retryable: true},
+		{name: "internal error", err: status(http.StatusInternalServerError, `{"status":500}`)},
+		{name: "not found", err: status(http.StatusNotFound, `{"status":404}`), invalid: true},
+		{name: "invalid access token", err: status(http.StatusUnauthorized, `{"error":"invalid access token"}`), invalid: true},
+		{name: "invalid arguments", err: invalidArgs, invalid: true},
+		{name: "circuit open", err: twipla3as.ErrCircuitOpen, temporary: true, retryable: true},
+		{name: "canceled", err: context.Canceled},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, retryable: true},
+		{name: "retries exhausted", err: &twipla3as.RetryError{Attempts: 3, Err: twipla3as.ErrCircuitOpen}, temporary: true, retryable: true},
+		{name: "nil"},
+	} {
+		assert.Equal(t, tc.temporary, twipla3as.IsTemporary(tc.err), "IsTemporary(%s)", tc.name)
+		assert.Equal(t, tc.retryable, twipla3as.IsRetryable(tc.err), "IsRetryable(%s)", tc.name)
+		assert.Equal(t, tc.invalid, twipla3as.IsClientError(tc.err), "IsClientError(%s)", tc.name)
+	}
+}
+
+// timeoutError is a network error reporting a timeout, such as a dial or read timeout.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
@@ -0,0 +1,114 @@
+package twipla3as
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// FieldError describes why the value of a single field of a request was rejected.
+type FieldError struct {
+	// Field is the name of the rejected field, such as "domain" or "intpCustomerId".
+	// Nested fields are separated by dots, such as "websites.0.domain". It is empty when the API does not name the field.
+	Field string
+	// Reason explains why the value was rejected, such as "must be a valid domain".
+	Reason string
+}
+
+func (e FieldError) Error() string {
+	if e.Field == "" {
+		return e.Reason
+	}
+	return e.Field + " " + e.Reason
+}
+
+// UnmarshalJSON decodes an error response of the API.
+// Besides a plain message, validation failures may come as a list of messages, such as
+// `"message": ["domain must be a valid domain"]`, or as a list of field details under "errors" or "details".
+func (e *APIError) UnmarshalJSON(data []byte) error {
+	type plain APIError
+	var body struct {
+		plain
+		Message json.RawMessage `json:"message"`
+		Errors  json.RawMessage `json:"errors"`
+		Details json.RawMessage `json:"details"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return err
+	}
+	*e = APIError(body.plain)
+
+	var messages []string
+	if err := json.Unmarshal(body.Message, &e.Message); err != nil && json.Unmarshal(body.Message, &messages) == nil {
+		e.Message = strings.Join(messages, "; ")
+		for _, message := range messages {
+			e.Fields = append(e.Fields, parseFieldMessage(message))
+		}
+	}
+	for _, raw := range []json.RawMessage{body.Errors, body.Details} {
+		var details []fieldDetail
+		if json.Unmarshal(raw, &details) != nil {
+			continue
+		}
+		for _, detail := range details {
+			e.Fields = append(e.Fields, detail.fieldErrors()...)
+		}
+	}
+	return nil
+}
+
+// parseFieldMessage splits a message such as "domain must be a valid domain" into the field it starts with and the reason.
+func parseFieldMessage(message string) FieldError {
+	field, reason, ok := strings.Cut(message, " ")
+	if !ok || !isFieldName(field) {
+		return FieldError{Reason: message}
+	}
+	return FieldError{Field: field, Reason: reason}
+}
+
+// isFieldName reports whether s looks like a field name, possibly nested, rather than the first word of a sentence.
+// Field names of the API are in lower camel case, while sentences start with a capital letter.
+func isFieldName(s string) bool {
+	if s == "" || s[0] < 'a' || s[0] > 'z' {
+		return false
+	}
+	for _, r := range s {
+		if !(r == '.' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldDetail is one entry of the "errors" or "details" list of an error response.
+type fieldDetail struct {
+	Field       string            `json:"field"`
+	Property    string            `json:"property"`
+	Path        string            `json:"path"`
+	Reason      string            `json:"reason"`
+	Message     string            `json:"message"`
+	Constraints map[string]string `json:"constraints"`
+	Children    []fieldDetail     `json:"children"`
+}
+
+func (d fieldDetail) fieldErrors() []FieldError {
+	field := cmp.Or(d.Field, d.Property, d.Path)
+	var fields []FieldError
+	if reason := cmp.Or(d.Reason, d.Message); reason != "" {
+		fields = append(fields, FieldError{Field: field, Reason: reason})
+	}
+	for _, name := range slices.Sorted(maps.Keys(d.Constraints)) {
+		fields = append(fields, FieldError{Field: field, Reason: strings.TrimPrefix(d.Constraints[name], field+" ")})
+	}
+	for _, child := range d.Children {
+		for _, f := range child.fieldErrors() {
+			if field != "" {
+				f.Field = field + "." + f.Field
+			}
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,56 @@
+package twipla3as_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+func TestFieldErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		body    string
+		message string
+		fields  []twipla3as.FieldError
+	}{
+		{
+			name:    "list of messages",
+			body:    `{"status":400,"message":["domain must be a valid domain","Package is required"],"error":"Bad Request"}`,
+			message: "domain must be a valid domain; Package is required",
+			fields: []twipla3as.FieldError{
+				{Field: "domain", Reason: "must be a valid domain"},
+				{Reason: "Package is required"},
+			},
+		},
+		{
+			name:    "list of details",
+			body:    `{"status":422,"message":"Validation failed","errors":[{"field":"intpCustomerId","reason":"is already used"},{"property":"website","children":[{"property":"domain","constraints":{"isFqdn":"domain must be a valid domain"}}]}]}`,
+			message: "Validation failed",
+			fields: []twipla3as.FieldError{
+				{Field: "intpCustomerId", Reason: "is already used"},
+				{Field: "website.domain", Reason: "must be a valid domain"},
+			},
+		},
+		{
+			name:    "plain message",
+			body:    `{"status":400,"message":"invalid package"}`,
+			message: "invalid package",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+				return jsonResponse(r, http.StatusBadRequest, tc.body), nil
+			})
+
+			_, err := sdk.CreatePackage(t.Context(), twipla3as.CreatePackageArgs{Name: "Basic", Currency: twipla3as.CurrencyEUR, Period: twipla3as.PeriodMonthly})
+			var apiErr twipla3as.APIError
+			if assert.ErrorAs(t, err, &apiErr) {
+				assert.Equal(t, tc.message, apiErr.Message)
+				assert.Equal(t, tc.fields, apiErr.Fields)
+			}
+			assert.ErrorIs(t, err, twipla3as.ErrValidation)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package twipla3as_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+// roundTripFunc allows using a plain function as an [http.RoundTripper], so tests can run without a network.
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+var (
+	testKeyOnce sync.Once
+	testKeyPEM  string
+)
+
+// testPrivateKey returns a PEM encoded RSA key generated once per test run.
+func testPrivateKey(t testing.TB) string {
+	t.Helper()
+	testKeyOnce.Do(func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			panic(err)
+		}
+		testKeyPEM = string(pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		}))
+	})
+	return testKeyPEM
+}
+
+// newTestSDK creates an SDK whose requests are all answered by rt.
+// The configuration can be further adjusted by passing configure functions.
+func newTestSDK(t testing.TB, rt roundTripFunc, configure ...func(*twipla3as.TwiplaConfig)) *twipla3as.TwiplaSDK {
+	t.Helper()
+	config := &twipla3as.TwiplaConfig{
+		IntpID:      "test-intp",
+		PrivateKey:  testPrivateKey(t),
+		Environment: twipla3as.EnvironmentDevelop,
+		HTTPClient:  &http.Client{Transport: rt},
+	}
+	for _, f := range configure {
+		f(config)
+	}
+	sdk, err := twipla3as.NewSDK(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sdk
+}
+
+// jsonResponse builds a JSON response with the given status code and body.
+func jsonResponse(r *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    r,
+	}
+}
@@ -0,0 +1,58 @@
+package twipla3as
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const (
+	// maxHTTPErrorBody is the number of bytes of the response body kept by [HTTPError].
+	maxHTTPErrorBody = 4 << 10
+	// maxAPIErrorBody is the number of bytes read from JSON error responses. Larger ones are reported as a truncated [HTTPError].
+	maxAPIErrorBody = 1 << 20
+)
+
+// HTTPError is returned for responses that are not in the format of the 3AS API, such as an HTML 502 page returned by a gateway.
+// It can be matched against sentinel errors such as [ErrServerError] with [errors.Is].
+type HTTPError struct {
+	StatusCode  int
+	ContentType string
+	Header      http.Header
+	// Body holds the start of the response body, truncated to 4 KiB.
+	Body string
+	// Truncated reports whether Body was truncated.
+	Truncated bool
+}
+
+func newHTTPError(resp *http.Response, body []byte) HTTPError {
+	e := HTTPError{
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Header:      resp.Header.Clone(),
+	}
+	if len(body) > maxHTTPErrorBody {
+		body = body[:maxHTTPErrorBody]
+		e.Truncated = true
+	}
+	e.Body = string(body)
+	return e
+}
+
+func (e HTTPError) Error() string {
+	msg := fmt.Sprintf("unexpected HTTP response: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.ContentType != "" {
+		msg += " (Content-Type: " + e.ContentType + ")"
+	}
+	if e.Body != "" {
+		msg += fmt.Sprintf(": %q", e.Body)
+		if e.Truncated {
+			msg += "..."
+		}
+	}
+	return msg
+}
+
+// Is reports whether e matches target, one of the sentinel errors such as [ErrServerError].
+func (e HTTPError) Is(target error) bool {
+	return statusIs(e.StatusCode, target)
+}
@@ -0,0 +1,109 @@
+package twipla3as_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+func TestHTTPError(t *testing.T) {
+	t.Run("non-JSON error response", func(t *testing.T) {
+		page := "<html>" + strings.Repeat("Bad Gateway ", 1000) + "</html>"
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusBadGateway,
+				Header:     http.Header{"Content-Type": []string{"text/html"}, "Server": []string{"gateway"}},
+				Body:       io.NopCloser(strings.NewReader(page)),
+				Request:    r,
+			}, nil
+		}, fastRetries(1, false))
+
+		_, err := sdk.INTPC(t.Context(), "intpc-id")
+		var httpErr twipla3as.HTTPError
+		if assert.ErrorAs(t, err, &httpErr) {
+			assert.Equal(t, http.StatusBadGateway, httpErr.StatusCode)
+			assert.Equal(t, "text/html", httpErr.ContentType)
+			assert.Equal(t, "gateway", httpErr.Header.Get("Server"))
+			assert.True(t, httpErr.Truncated)
+			assert.Len(t, httpErr.Body, 4096)
+			assert.True(t, strings.HasPrefix(httpErr.Body, "<html>Bad Gateway"))
+		}
+		assert.ErrorIs(t, err, twipla3as.ErrServerError)
+		assert.Contains(t, err.Error(), "502 Bad Gateway (Content-Type: text/html)")
+	})
+
+	t.Run("error bodies are read up to a limit", func(t *testing.T) {
+		for contentType, limit := range map[string]int{"text/html": 4<<10 + 1, "application/json": 1<<20 + 1} {
+			body := &countingReader{r: io.MultiReader(strings.NewReader(`{"status":502,"message":"`), endless{})}
+			sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusBadGateway,
+					Header:     http.Header{"Content-Type": []string{contentType}},
+					Body:       io.NopCloser(body),
+					Request:    r,
+				}, nil
+			}, fastRetries(1, false))
+
+			_, err := sdk.INTPC(t.Context(), "intpc-id")
+			var httpErr twipla3as.HTTPError
+			if assert.ErrorAs(t, err, &httpErr, contentType) {
+				assert.True(t, httpErr.Truncated)
+			}
+			assert.LessOrEqual(t, body.n, limit, contentType)
+		}
+	})
+
+	t.Run("unexpected content type", func(t *testing.T) {
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("maintenance")),
+				Request:    r,
+			}, nil
+		})
+
+		_, err := sdk.INTPC(t.Context(), "intpc-id")
+		var httpErr twipla3as.HTTPError
+		if assert.ErrorAs(t, err, &httpErr) {
+			assert.Equal(t, http.StatusOK, httpErr.StatusCode)
+			assert.Equal(t, "maintenance", httpErr.Body)
+			assert.False(t, httpErr.Truncated)
+		}
+	})
+
+	t.Run("API error includes the error field", func(t *testing.T) {
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(r, http.StatusBadRequest, `{"status":400,"message":"bad request","error":"email is taken"}`), nil
+		})
+
+		_, err := sdk.INTPC(t.Context(), "intpc-id")
+		assert.ErrorContains(t, err, "(Error: email is taken)")
+	})
+}
+
+// endless is an endless stream of "x".
+type endless struct{}
+
+func (endless) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 'x'
+	}
+	return len(p), nil
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
@@ -0,0 +1,70 @@
+package twipla3as
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// IdempotencyKeyHeader is the header carrying the idempotency key of mutating requests.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyKeyKey struct{}
+
+// WithIdempotencyKey returns a context making the mutating call it is passed to, such as [TwiplaSDK.CreateINTPC],
+// use key as its idempotency key. Reusing the same key when calling again after a crash or a dropped connection
+// marks the call as a replay of the previous one.
+//
+// Without it, every mutating call gets a random key, shared by its own retries.
+// Passing a key also opts the call into retries, even if [RetryPolicy.RetryUnsafeMethods] is not set.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey{}, key)
+}
+
+func idempotencyKeyFrom(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyKey{}).(string)
+	return key, ok && key != ""
+}
+
+// setIdempotencyKey sets the idempotency key header of a mutating request, and reports whether the key was supplied by the caller.
+func setIdempotencyKey(ctx context.Context, r *http.Request) (bool, error) {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return false, nil
+	}
+	key, supplied := idempotencyKeyFrom(ctx)
+	if !supplied {
+		var err error
+		if key, err = newIdempotencyKey(); err != nil {
+			return false, err
+		}
+	}
+	r.Header.Set(IdempotencyKeyHeader, key)
+	return supplied, nil
+}
+
+// newIdempotencyKey generates a random (version 4) UUID.
+func newIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("can't generate idempotency key: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// isReplayConflict reports whether err is an "already exists" error caused by replaying a creation
+// that already succeeded: either the SDK retried the call itself, or the caller supplied an idempotency key.
+func isReplayConflict(ctx context.Context, err error) bool {
+	if !errors.Is(err, ErrAlreadyExists) {
+		return false
+	}
+	var retryErr *RetryError
+	if errors.As(err, &retryErr) && retryErr.Attempts > 1 {
+		return true
+	}
+	_, supplied := idempotencyKeyFrom(ctx)
+	return supplied
+}
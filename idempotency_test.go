@@ -0,0 +1,86 @@
+package twipla3as_test
+
+import (
+	"net/http"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	createArgs := twipla3as.CreateINTPCArgs{
+		ExternalCustomerID: "intpc-id",
+		Email:              "customer@twipla.com",
+		SubscriptionType:   twipla3as.SubscriptionTypeINTPC,
+		PackageID:          "package-id",
+		ExternalWebsiteID:  "website-id",
+		Domain:             "twipla.com",
+	}
+	existing := `{"payload":{"id":"intpc-uuid","intpCustomerID":"intpc-id","email":"customer@twipla.com"}}`
+	conflict := `{"status":409,"message":"customer already exists"}`
+
+	t.Run("keys are generated and kept across retries", func(t *testing.T) {
+		var keys []string
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			keys = append(keys, r.Header.Get(twipla3as.IdempotencyKeyHeader))
+			if len(keys) == 1 {
+				return nil, syscall.ECONNRESET
+			}
+			return jsonResponse(r, http.StatusOK, `{"payload":null}`), nil
+		}, fastRetries(2, true))
+
+		assert.NoError(t, sdk.AddWebsiteWhitelistedDomain(t.Context(), "website-id", "twipla.com"))
+		if assert.Len(t, keys, 2) {
+			assert.Len(t, keys[0], 36)
+			assert.Equal(t, keys[0], keys[1])
+		}
+	})
+
+	t.Run("retried creation is reconciled", func(t *testing.T) {
+		var calls int
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			calls++
+			switch {
+			case r.Method == http.MethodGet:
+				return jsonResponse(r, http.StatusOK, existing), nil
+			case calls == 1:
+				return nil, syscall.ECONNRESET
+			default:
+				return jsonResponse(r, http.StatusConflict, conflict), nil
+			}
+		}, fastRetries(2, false))
+
+		ctx := twipla3as.WithIdempotencyKey(t.Context(), "signup-42")
+		intpc, err := sdk.CreateINTPC(ctx, createArgs)
+		assert.NoError(t, err)
+		assert.Equal(t, "intpc-uuid", intpc.ID)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("genuine conflicts are reported", func(t *testing.T) {
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			assert.NotEqual(t, http.MethodGet, r.Method)
+			return jsonResponse(r, http.StatusConflict, conflict), nil
+		})
+
+		_, err := sdk.CreateINTPC(t.Context(), createArgs)
+		var apiErr twipla3as.APIError
+		assert.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.Status)
+	})
+
+	t.Run("mismatching resources are not reconciled", func(t *testing.T) {
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			if r.Method == http.MethodGet {
+				return jsonResponse(r, http.StatusOK, `{"payload":{"id":"website-uuid","intpCustomerId":"someone-else","domain":"twipla.com"}}`), nil
+			}
+			return jsonResponse(r, http.StatusConflict, `{"status":409,"message":"website already exists"}`), nil
+		})
+
+		ctx := twipla3as.WithIdempotencyKey(t.Context(), "website-42")
+		err := sdk.CreateWebsite(ctx, twipla3as.CreateWebsiteArgs{ExternalID: "website-id", IntpcID: "intpc-id", Domain: "twipla.com"})
+		assert.Error(t, err)
+	})
+}
@@ -3,7 +3,7 @@ package twipla3as
 import (
 	"context"
 	"net/http"
-	"path"
+	"strings"
 	"time"
 )
 
@@ -57,7 +57,12 @@ type CreateINTPCArgs struct {
 	Domain string
 }
 
+// CreateINTPC registers a new customer along with its first website and subscription.
+// When a replayed call (see [WithIdempotencyKey]) finds the customer already created, the existing customer is returned.
 func (sdk *TwiplaSDK) CreateINTPC(ctx context.Context, args CreateINTPCArgs) (INTPC, error) {
+	if err := args.Validate(); err != nil {
+		return INTPC{}, err
+	}
 	if args.BillingDate.IsZero() {
 		args.BillingDate = time.Now()
 	}
@@ -71,12 +76,18 @@ func (sdk *TwiplaSDK) CreateINTPC(ctx context.Context, args CreateINTPCArgs) (IN
 	case SubscriptionTypeINTPC:
 		apiArgs.PackageID = args.PackageID
 		apiArgs.BillingDate = args.BillingDate.UTC().Format(time.RFC3339)
-	default:
-		return INTPC{}, ErrInvalidSubscriptionType
 	}
 	apiArgs.Website.IntpWebsiteID = args.ExternalWebsiteID
 	apiArgs.Website.Domain = args.Domain
-	resp, err := parseResponse[INTPC](sdk.apiCall(ctx, http.MethodPost, "/v2/3as/customers", apiArgs))
+	op := newOperation("CreateINTPC", http.MethodPost, "/v2/3as/customers").forIntpc(args.ExternalCustomerID).forWebsite(args.ExternalWebsiteID)
+	resp, err := parseResponse[INTPC](sdk.apiCall(ctx, op, apiArgs))
+	if isReplayConflict(ctx, err) {
+		// The customer was created by an earlier attempt, so it is returned if it matches the arguments.
+		existing, getErr := sdk.INTPC(ctx, args.ExternalCustomerID)
+		if getErr == nil && strings.EqualFold(existing.Email, args.Email) {
+			return existing, nil
+		}
+	}
 	if err != nil {
 		return INTPC{}, err
 	}
@@ -85,7 +96,7 @@ func (sdk *TwiplaSDK) CreateINTPC(ctx context.Context, args CreateINTPCArgs) (IN
 
 func (sdk *TwiplaSDK) INTPCs(ctx context.Context, pagination Pagination) ([]INTPC, PaginationMetadata, error) {
 	query := pagination.buildQuery()
-	resp, err := parseResponse[[]INTPC](sdk.apiCall(ctx, http.MethodGet, "/v2/3as/customers", query))
+	resp, err := parseResponse[[]INTPC](sdk.apiCall(ctx, newOperation("INTPCs", http.MethodGet, "/v2/3as/customers"), query))
 	if err != nil {
 		return nil, PaginationMetadata{}, err
 	}
@@ -95,7 +106,7 @@ func (sdk *TwiplaSDK) INTPCs(ctx context.Context, pagination Pagination) ([]INTP
 
 // INTPC gets an INTPC/customer based on the INTP's own customer ID.
 func (sdk *TwiplaSDK) INTPC(ctx context.Context, intpcID string) (INTPC, error) {
-	resp, err := parseResponse[INTPC](sdk.apiCall(ctx, http.MethodGet, path.Join("/v2/3as/customers", intpcID), nil))
+	resp, err := parseResponse[INTPC](sdk.apiCall(ctx, newOperation("INTPC", http.MethodGet, "/v2/3as/customers/{intpcId}", intpcID), nil))
 	if err != nil {
 		return INTPC{}, err
 	}
@@ -104,7 +115,7 @@ func (sdk *TwiplaSDK) INTPC(ctx context.Context, intpcID string) (INTPC, error)
 
 // DeleteINTPC removes an INTPC and its linked websites.
 func (sdk *TwiplaSDK) DeleteINTPC(ctx context.Context, intpcID string) (INTPC, error) {
-	resp, err := parseResponse[INTPC](sdk.apiCall(ctx, http.MethodDelete, path.Join("/v2/3as/customers", intpcID), nil))
+	resp, err := parseResponse[INTPC](sdk.apiCall(ctx, newOperation("DeleteINTPC", http.MethodDelete, "/v2/3as/customers/{intpcId}", intpcID), nil))
 	if err != nil {
 		return INTPC{}, err
 	}
@@ -0,0 +1,101 @@
+package twipla3as
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNoSigningKey is returned when signing with a previous key of a [KeyRing] whose grace period is over.
+var ErrNoSigningKey = errors.New("no signing key")
+
+// DefaultPrimaryRetryInterval is used when [KeyRing.PrimaryRetryInterval] is zero.
+const DefaultPrimaryRetryInterval = time.Minute
+
+// KeyRing is a [Signer] holding several INTP keys, to rotate them without downtime.
+// Tokens are signed with the primary key. The keys it replaced are kept for a grace period, during which
+// the SDK falls back to them when the API gateway answers with [ErrInvalidAccessToken], as it does until it knows the new key.
+// INTPC tokens are then signed with the same key as INTP tokens.
+// The primary key is tried again every PrimaryRetryInterval, so that the SDK moves on to it once the API gateway knows it.
+//
+// Each key sets its own "kid" header, such as with the keyID given to [NewCryptoSigner].
+// A KeyRing is safe for concurrent use, and its primary key can be replaced at any time with [KeyRing.Rotate].
+type KeyRing struct {
+	// PrimaryRetryInterval is how long the SDK signs with a previous key after falling back to it,
+	// before trying the primary key again. It defaults to [DefaultPrimaryRetryInterval].
+	// It must not be changed once the KeyRing is in use.
+	PrimaryRetryInterval time.Duration
+
+	mu sync.Mutex
+	// keys holds the primary key, then the previous keys from the most recent one.
+	keys []ringKey
+	// generation changes whenever the primary key is replaced.
+	generation uint64
+}
+
+type ringKey struct {
+	signer Signer
+	// retiresAt is the end of the grace period of a previous key.
+	retiresAt time.Time
+}
+
+// NewKeyRing returns a [KeyRing] signing tokens with primary.
+func NewKeyRing(primary Signer) *KeyRing {
+	return &KeyRing{keys: []ringKey{{signer: primary}}}
+}
+
+// Rotate makes key the primary key, used to sign all new tokens.
+// The replaced primary key remains available as a fallback for grace.
+// Cached tokens are dropped, so that the SDK signs fresh ones with key.
+func (k *KeyRing) Rotate(key Signer, grace time.Duration) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.prune()
+	k.keys[0].retiresAt = time.Now().Add(grace)
+	k.keys = append([]ringKey{{signer: key}}, k.keys...)
+	k.generation++
+}
+
+// SignJWT signs claims with the primary key.
+func (k *KeyRing) SignJWT(ctx context.Context, claims map[string]any) (string, error) {
+	return k.signWith(ctx, claims, 0)
+}
+
+// signWith signs claims with the key at the given level: 0 for the primary key, then the previous keys still in their grace period.
+func (k *KeyRing) signWith(ctx context.Context, claims map[string]any, level int) (string, error) {
+	k.mu.Lock()
+	k.prune()
+	if level >= len(k.keys) {
+		k.mu.Unlock()
+		return "", ErrNoSigningKey
+	}
+	signer := k.keys[level].signer
+	k.mu.Unlock()
+	return signer.SignJWT(ctx, claims)
+}
+
+// state returns the number of keys that can sign tokens, and the generation of the primary key.
+func (k *KeyRing) state() (levels int, generation uint64) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.prune()
+	return len(k.keys), k.generation
+}
+
+// prune drops the previous keys whose grace period is over. k.mu must be held.
+func (k *KeyRing) prune() {
+	now := time.Now()
+	kept := k.keys[:1]
+	for _, key := range k.keys[1:] {
+		if now.Before(key.retiresAt) {
+			kept = append(kept, key)
+		}
+	}
+	k.keys = kept
+}
+
+// RotateKey makes key the primary signing key of the SDK, without rebuilding it. See [KeyRing.Rotate].
+func (sdk *TwiplaSDK) RotateKey(key Signer, grace time.Duration) {
+	sdk.signer.keys.Rotate(key, grace)
+}
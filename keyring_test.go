@@ -0,0 +1,155 @@
+package twipla3as_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+func newKeySigner(t *testing.T, keyID string) twipla3as.Signer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := twipla3as.NewCryptoSigner(key, keyID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+// tokenKeyID returns the "kid" header of a token, without verifying it.
+func tokenKeyID(t *testing.T, token string) string {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(strings.TrimPrefix(token, "Bearer "), jwt.MapClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func TestKeyRotation(t *testing.T) {
+	t.Run("fallback to the previous key during the rotation window", func(t *testing.T) {
+		accepted := "key-1"
+		var kids, bodies []string
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			kid := tokenKeyID(t, r.Header.Get("Authorization"))
+			kids = append(kids, kid)
+			if r.Body != nil {
+				body, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+			}
+			if kid != accepted {
+				return jsonResponse(r, http.StatusUnauthorized, `{"status":401,"error":"invalid access token"}`), nil
+			}
+			return jsonResponse(r, http.StatusOK, `{"payload":{"id":"package-id"}}`), nil
+		}, func(c *twipla3as.TwiplaConfig) {
+			c.PrivateKey = ""
+			c.Signer = newKeySigner(t, "key-1")
+		})
+
+		_, err := sdk.Package(t.Context(), "package-id")
+		assert.NoError(t, err)
+
+		// The gateway does not know the new key yet.
+		sdk.RotateKey(newKeySigner(t, "key-2"), time.Hour)
+		_, err = sdk.UpdatePackage(t.Context(), "package-id", twipla3as.UpdatePackageArgs{Name: "Pro"})
+		assert.NoError(t, err)
+		_, err = sdk.Package(t.Context(), "package-id")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"key-1", "key-2", "key-1", "key-1"}, kids)
+		assert.Equal(t, []string{`{"name":"Pro"}`, `{"name":"Pro"}`}, bodies)
+
+		// Dashboard URLs are signed with the key the gateway accepts.
+		iframeURL, err := sdk.GenerateIframeURL("intpc-id", "website-id")
+		assert.NoError(t, err)
+		assert.Equal(t, "key-1", tokenKeyID(t, strings.SplitN(iframeURL, "intpc_token=", 2)[1]))
+
+		// The gateway learned the new key and dropped the previous one.
+		accepted = "key-2"
+		_, err = sdk.Package(t.Context(), "package-id")
+		assert.NoError(t, err)
+		_, err = sdk.Package(t.Context(), "package-id")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"key-1", "key-2", "key-1", "key-1", "key-1", "key-2", "key-2"}, kids)
+
+		iframeURL, err = sdk.GenerateIframeURL("intpc-id", "website-id")
+		assert.NoError(t, err)
+		assert.Equal(t, "key-2", tokenKeyID(t, strings.SplitN(iframeURL, "intpc_token=", 2)[1]))
+	})
+
+	t.Run("the primary key is tried again", func(t *testing.T) {
+		accepted := "key-1"
+		var kids []string
+		ring := twipla3as.NewKeyRing(newKeySigner(t, "key-1"))
+		ring.PrimaryRetryInterval = 50 * time.Millisecond
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			kid := tokenKeyID(t, r.Header.Get("Authorization"))
+			kids = append(kids, kid)
+			if kid != accepted {
+				return jsonResponse(r, http.StatusUnauthorized, `{"status":401,"error":"invalid access token"}`), nil
+			}
+			return jsonResponse(r, http.StatusOK, `{"payload":[]}`), nil
+		}, func(c *twipla3as.TwiplaConfig) {
+			c.Signer = ring
+		})
+
+		ring.Rotate(newKeySigner(t, "key-2"), time.Hour)
+		_, err := sdk.Packages(t.Context())
+		assert.NoError(t, err)
+		_, err = sdk.Packages(t.Context())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"key-2", "key-1", "key-1"}, kids)
+
+		// The gateway learned the new key, which is used again once the retry interval is over.
+		accepted = "key-2"
+		time.Sleep(60 * time.Millisecond)
+		_, err = sdk.Packages(t.Context())
+		assert.NoError(t, err)
+		_, err = sdk.Packages(t.Context())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"key-2", "key-1", "key-1", "key-2", "key-2"}, kids)
+	})
+
+	t.Run("no fallback once the grace period is over", func(t *testing.T) {
+		var kids []string
+		ring := twipla3as.NewKeyRing(newKeySigner(t, "key-1"))
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			kid := tokenKeyID(t, r.Header.Get("Authorization"))
+			kids = append(kids, kid)
+			if kid != "key-1" {
+				return jsonResponse(r, http.StatusUnauthorized, `{"status":401,"error":"invalid access token"}`), nil
+			}
+			return jsonResponse(r, http.StatusOK, `{"payload":[]}`), nil
+		}, func(c *twipla3as.TwiplaConfig) {
+			c.Signer = ring
+		})
+
+		ring.Rotate(newKeySigner(t, "key-2"), 0)
+		_, err := sdk.Packages(t.Context())
+		assert.ErrorIs(t, err, twipla3as.ErrInvalidAccessToken)
+		assert.Equal(t, []string{"key-2"}, kids)
+	})
+
+	t.Run("key ID of the private key", func(t *testing.T) {
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			assert.Equal(t, "2026-10", tokenKeyID(t, r.Header.Get("Authorization")))
+			return jsonResponse(r, http.StatusOK, `{"payload":[]}`), nil
+		}, func(c *twipla3as.TwiplaConfig) {
+			c.KeyID = "2026-10"
+		})
+
+		_, err := sdk.Packages(t.Context())
+		assert.NoError(t, err)
+	})
+}
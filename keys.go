@@ -0,0 +1,198 @@
+package twipla3as
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+var (
+	// ErrIncorrectPassphrase is returned for encrypted private keys when the passphrase is missing or wrong.
+	ErrIncorrectPassphrase = errors.New("incorrect private key passphrase")
+	// ErrInvalidPrivateKey is returned for private keys that cannot be parsed.
+	ErrInvalidPrivateKey = errors.New("invalid private key")
+)
+
+// ParsePrivateKey parses an RSA, ECDSA or Ed25519 private key, to be used with [NewCryptoSigner].
+//
+// data holds either a PEM block or its DER contents, in the PKCS#1, SEC 1 or PKCS#8 formats.
+// Keys encrypted with a passphrase are accepted, both as PKCS#8 "ENCRYPTED PRIVATE KEY" blocks (PBES2, with PBKDF2 and AES-CBC)
+// and as legacy OpenSSL encrypted PEM blocks.
+func ParsePrivateKey(data []byte, passphrase string) (crypto.Signer, error) {
+	der := data
+	var err error
+	block, _ := pem.Decode(data)
+	switch {
+	case block == nil:
+		if isEncryptedPKCS8(data) {
+			der, err = decryptPKCS8(data, passphrase)
+		}
+	case block.Type == "ENCRYPTED PRIVATE KEY":
+		der, err = decryptPKCS8(block.Bytes, passphrase)
+	case x509.IsEncryptedPEMBlock(block):
+		der, err = decryptPEMBlock(block, passphrase)
+	default:
+		der = block.Bytes
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseDERPrivateKey(der)
+}
+
+// decryptPEMBlock decrypts a legacy OpenSSL encrypted PEM block.
+// Such blocks are deprecated as insecure by design, but are still produced by OpenSSL tooling.
+func decryptPEMBlock(block *pem.Block, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, ErrIncorrectPassphrase
+	}
+	der, err := x509.DecryptPEMBlock(block, []byte(passphrase))
+	if err != nil {
+		return nil, ErrIncorrectPassphrase
+	}
+	return der, nil
+}
+
+func parseDERPrivateKey(der []byte) (crypto.Signer, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("%w: %T", ErrUnsupportedKey, key)
+		}
+		return signer, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: not a PKCS#1, SEC 1 or PKCS#8 key", ErrInvalidPrivateKey)
+}
+
+var (
+	oidPBES2      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+	oidAES128CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+// encryptedPrivateKeyInfo is the PKCS#8 structure of encrypted keys, defined in RFC 5208.
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// pbes2Params are the parameters of the PBES2 encryption scheme, defined in RFC 8018.
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+func isEncryptedPKCS8(der []byte) bool {
+	var info encryptedPrivateKeyInfo
+	rest, err := asn1.Unmarshal(der, &info)
+	return err == nil && len(rest) == 0 && info.Algorithm.Algorithm.Equal(oidPBES2)
+}
+
+// decryptPKCS8 decrypts a PKCS#8 encrypted key, returning its unencrypted DER contents.
+func decryptPKCS8(der []byte, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, ErrIncorrectPassphrase
+	}
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPrivateKey, err)
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("%w: unsupported encryption %s, only PBES2 is supported", ErrInvalidPrivateKey, info.Algorithm.Algorithm)
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPrivateKey, err)
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("%w: unsupported key derivation %s, only PBKDF2 is supported", ErrInvalidPrivateKey, params.KeyDerivationFunc.Algorithm)
+	}
+	var kdf pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPrivateKey, err)
+	}
+
+	var keyLength int
+	switch alg := params.EncryptionScheme.Algorithm; {
+	case alg.Equal(oidAES128CBC):
+		keyLength = 16
+	case alg.Equal(oidAES192CBC):
+		keyLength = 24
+	case alg.Equal(oidAES256CBC):
+		keyLength = 32
+	default:
+		return nil, fmt.Errorf("%w: unsupported cipher %s, only AES-CBC is supported", ErrInvalidPrivateKey, alg)
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil || len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("%w: invalid AES-CBC IV", ErrInvalidPrivateKey)
+	}
+
+	var prf func() hash.Hash
+	switch alg := kdf.PRF.Algorithm; {
+	case len(alg) == 0 || alg.Equal(oidHMACSHA1):
+		prf = sha1.New
+	case alg.Equal(oidHMACSHA256):
+		prf = sha256.New
+	case alg.Equal(oidHMACSHA512):
+		prf = sha512.New
+	default:
+		return nil, fmt.Errorf("%w: unsupported PBKDF2 PRF %s", ErrInvalidPrivateKey, alg)
+	}
+	key, err := pbkdf2.Key(prf, passphrase, kdf.Salt, kdf.IterationCount, keyLength)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPrivateKey, err)
+	}
+
+	data := info.EncryptedData
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("%w: invalid encrypted data length", ErrInvalidPrivateKey)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, data)
+
+	// A wrong passphrase shows up as invalid PKCS#7 padding, or as garbage that is not a key.
+	padding := int(plain[len(plain)-1])
+	if padding == 0 || padding > aes.BlockSize || !bytes.Equal(plain[len(plain)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, ErrIncorrectPassphrase
+	}
+	plain = plain[:len(plain)-padding]
+	if _, err := x509.ParsePKCS8PrivateKey(plain); err != nil {
+		return nil, ErrIncorrectPassphrase
+	}
+	return plain, nil
+}
@@ -0,0 +1,96 @@
+package twipla3as_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+func readTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestPrivateKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	assert.NoError(t, err)
+	encryptedEC, _ := pem.Decode(readTestdata(t, "ec_encrypted.pem"))
+
+	for _, tc := range []struct {
+		name       string
+		key        string
+		passphrase string
+		alg        twipla3as.Algorithm
+		wantAlg    string
+	}{
+		{name: "PKCS#1", key: testPrivateKey(t), wantAlg: "RS256"},
+		{name: "PKCS#8 PEM", key: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})), alg: twipla3as.AlgorithmPS256, wantAlg: "PS256"},
+		{name: "PKCS#8 DER", key: string(pkcs8), alg: twipla3as.AlgorithmRS512, wantAlg: "RS512"},
+		{name: "encrypted PKCS#8", key: string(readTestdata(t, "ec_encrypted.pem")), passphrase: "secret", wantAlg: "ES256"},
+		{name: "encrypted PKCS#8 DER", key: string(encryptedEC.Bytes), passphrase: "secret", wantAlg: "ES256"},
+		{name: "legacy encrypted PEM", key: string(readTestdata(t, "rsa_legacy_encrypted.pem")), passphrase: "secret", wantAlg: "RS256"},
+		{name: "Ed25519 DER", key: string(readTestdata(t, "ed25519.der")), wantAlg: "EdDSA"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := twipla3as.ParsePrivateKey([]byte(tc.key), tc.passphrase)
+			if !assert.NoError(t, err) {
+				return
+			}
+			sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+				token, err := jwt.Parse(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), func(token *jwt.Token) (any, error) {
+					return key.Public(), nil
+				}, jwt.WithValidMethods([]string{tc.wantAlg}))
+				assert.NoError(t, err)
+				assert.Equal(t, "test-intp", token.Header["kid"])
+				return jsonResponse(r, http.StatusOK, `{"payload":[]}`), nil
+			}, func(c *twipla3as.TwiplaConfig) {
+				c.PrivateKey = tc.key
+				c.PrivateKeyPassphrase = tc.passphrase
+				c.SigningAlgorithm = tc.alg
+			})
+
+			_, err = sdk.Packages(t.Context())
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			key        string
+			passphrase string
+			alg        twipla3as.Algorithm
+			err        error
+		}{
+			{key: string(readTestdata(t, "ec_encrypted.pem")), err: twipla3as.ErrIncorrectPassphrase},
+			{key: string(readTestdata(t, "ec_encrypted.pem")), passphrase: "wrong", err: twipla3as.ErrIncorrectPassphrase},
+			{key: string(readTestdata(t, "rsa_legacy_encrypted.pem")), passphrase: "wrong", err: twipla3as.ErrIncorrectPassphrase},
+			{key: "not a key", err: twipla3as.ErrInvalidPrivateKey},
+			{key: testPrivateKey(t), alg: twipla3as.AlgorithmES256, err: twipla3as.ErrUnsupportedKey},
+			{key: string(readTestdata(t, "ed25519.der")), alg: twipla3as.AlgorithmRS256, err: twipla3as.ErrUnsupportedKey},
+			{key: testPrivateKey(t), alg: "HS256", err: twipla3as.ErrUnsupportedAlgorithm},
+		} {
+			_, err := twipla3as.NewSDK(&twipla3as.TwiplaConfig{
+				IntpID:               "test-intp",
+				PrivateKey:           tc.key,
+				PrivateKeyPassphrase: tc.passphrase,
+				SigningAlgorithm:     tc.alg,
+			})
+			assert.ErrorIs(t, err, tc.err)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package twipla3as
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// maxLoggedBody is the maximum number of request body bytes included in failed call logs.
+const maxLoggedBody = 4 << 10
+
+// logged logs every API call with the SDK's logger.
+// Successful calls are logged at [slog.LevelDebug], and failed ones at [slog.LevelWarn], along with the request body.
+// Credentials and secrets are always redacted.
+func (sdk *TwiplaSDK) logged(next Handler) Handler {
+	return func(r *http.Request) (*http.Response, error) {
+		ctx := r.Context()
+		start := time.Now()
+		resp, err := next(r)
+
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Duration("duration", time.Since(start)),
+		}
+		if r.URL.RawQuery != "" {
+			attrs = append(attrs, slog.String("query", redactQuery(r.URL.RawQuery)))
+		}
+		if state := callStateFrom(ctx); state != nil && state.attempts > 1 {
+			attrs = append(attrs, slog.Int("attempts", state.attempts))
+		}
+		if err == nil {
+			attrs = append(attrs, slog.Int("status", resp.StatusCode))
+			if requestID := firstHeader(resp.Header, requestIDHeaders...); requestID != "" {
+				attrs = append(attrs, slog.String("request_id", requestID))
+			}
+			sdk.logger.LogAttrs(ctx, slog.LevelDebug, "3AS API call", attrs...)
+			return resp, err
+		}
+
+		var apiErr APIError
+		if errors.As(err, &apiErr) {
+			attrs = append(attrs, slog.Int("status", apiErr.Status), slog.Int("code", apiErr.Code))
+			if apiErr.Meta != nil && apiErr.Meta.RequestID != "" {
+				attrs = append(attrs, slog.String("request_id", apiErr.Meta.RequestID))
+			}
+		}
+		var httpErr HTTPError
+		if errors.As(err, &httpErr) {
+			attrs = append(attrs, slog.Int("status", httpErr.StatusCode), slog.String("content_type", httpErr.ContentType))
+		}
+		if body := loggedBody(r); body != "" {
+			attrs = append(attrs, slog.String("body", body))
+		}
+		attrs = append(attrs, slog.Any("error", err))
+		sdk.logger.LogAttrs(ctx, slog.LevelWarn, "3AS API call failed", attrs...)
+		return resp, err
+	}
+}
+
+// loggedBody returns the redacted request body, truncated to maxLoggedBody.
+func loggedBody(r *http.Request) string {
+	if r.GetBody == nil {
+		return ""
+	}
+	body, err := r.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return ""
+	}
+	data = redactJSON(data)
+	if len(data) > maxLoggedBody {
+		return string(data[:maxLoggedBody]) + "..."
+	}
+	return string(data)
+}
+
+// logIframeURL logs the generation of a dashboard URL, without its INTPC token.
+func (sdk *TwiplaSDK) logIframeURL(intpcID string, iframeURL string) {
+	if sdk.logger == nil {
+		return
+	}
+	sdk.logger.LogAttrs(context.Background(), slog.LevelDebug, "3AS iframe URL generated",
+		slog.String("intpc_id", intpcID),
+		slog.String("url", redactURL(iframeURL)),
+	)
+}
+
+// LogValue implements [slog.LogValuer], so that the API key secret is never logged.
+func (k ApiKey) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("id", k.Id),
+		slog.String("name", k.Name),
+		slog.String("intpWebsiteId", k.IntpWebsiteId),
+		slog.String("intpCustomerId", k.IntpCustomerId),
+	}
+	if k.ApiKey != nil {
+		attrs = append(attrs, slog.String("apiKey", redacted))
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,57 @@
+package twipla3as_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+func TestLogging(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+		if r.Method == http.MethodPost {
+			return jsonResponse(r, http.StatusBadRequest, `{"status":400,"message":"domain is blocked","code":1001}`), nil
+		}
+		return jsonResponse(r, http.StatusOK, `{"payload":[{"id":"key-id","name":"key","apiKey":"super-secret"}]}`), nil
+	}, func(c *twipla3as.TwiplaConfig) {
+		c.Logger = logger
+	})
+
+	keys, err := sdk.ListWebsiteApiKeys(t.Context(), "website-id")
+	assert.NoError(t, err)
+	logger.Info("listed keys", "key", keys[0])
+
+	err = sdk.CreateWebsite(t.Context(), twipla3as.CreateWebsiteArgs{
+		ExternalID: "website-id",
+		IntpcID:    "intpc-id",
+		Domain:     "blocked.example",
+	})
+	assert.Error(t, err)
+
+	iframeURL, err := sdk.GenerateIframeURL("intpc-id", "website-id")
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if assert.Len(t, lines, 4) {
+		assert.Contains(t, lines[0], `"level":"DEBUG"`)
+		assert.Contains(t, lines[0], `"path":"/v2/3as/websites/website-id/api-keys"`)
+		assert.Contains(t, lines[0], `"status":200`)
+		assert.Contains(t, lines[1], `"apiKey":"[REDACTED]"`)
+		assert.Contains(t, lines[2], `"level":"WARN"`)
+		assert.Contains(t, lines[2], `"status":400`)
+		assert.Contains(t, lines[2], `"code":1001`)
+		assert.Contains(t, lines[2], `blocked.example`)
+		assert.Contains(t, lines[3], `intpc_token=%5BREDACTED%5D`)
+	}
+	token, err := sdk.IntpAccessToken()
+	assert.NoError(t, err)
+	assert.NotContains(t, logs.String(), token)
+	assert.NotContains(t, logs.String(), "super-secret")
+	assert.NotContains(t, logs.String(), strings.SplitN(iframeURL, "intpc_token=", 2)[1])
+}
@@ -0,0 +1,121 @@
+package twipla3as
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Metrics receives a measurement for every operation of the SDK, such as [TwiplaSDK.Websites].
+// The metrics subpackage provides an implementation exposing counters and histograms in the Prometheus text format.
+type Metrics interface {
+	ObserveCall(call CallMetrics)
+}
+
+// CallMetrics describes the outcome of a single SDK operation.
+type CallMetrics struct {
+	// Operation is the name of the SDK method, such as "CreateINTPC".
+	Operation string
+	// Duration is the total duration of the call, including retries and rate limiting.
+	Duration time.Duration
+	// Status is the HTTP status code of the last response, or 0 if no response was received.
+	Status int
+	// ErrorClass classifies the error the call failed with. It is [ErrorClassNone] for successful calls.
+	ErrorClass ErrorClass
+}
+
+// ErrorClass is a coarse classification of errors, suitable as a metric label.
+type ErrorClass string
+
+const (
+	ErrorClassNone ErrorClass = ""
+	// ErrorClassCanceled is used when the call's context was canceled.
+	ErrorClassCanceled ErrorClass = "canceled"
+	// ErrorClassTimeout is used when the call's context deadline was exceeded, or a network timeout occurred.
+	ErrorClassTimeout ErrorClass = "timeout"
+	// ErrorClassNetwork is used when no response could be received from the API.
+	ErrorClassNetwork ErrorClass = "network"
+	// ErrorClassCircuitOpen is used when the call was rejected by the circuit breaker, see [ErrCircuitOpen].
+	ErrorClassCircuitOpen ErrorClass = "circuit_open"
+	// ErrorClassAuth is used for 401 and 403 responses, including [ErrInvalidAccessToken].
+	ErrorClassAuth ErrorClass = "auth"
+	// ErrorClassRateLimited is used for 429 responses.
+	ErrorClassRateLimited ErrorClass = "rate_limited"
+	// ErrorClassClient is used for the other 4xx responses.
+	ErrorClassClient ErrorClass = "client"
+	// ErrorClassServer is used for 5xx responses.
+	ErrorClassServer ErrorClass = "server"
+	// ErrorClassOther is used for any other error, such as an undecodable response.
+	ErrorClassOther ErrorClass = "other"
+)
+
+// observeCall reports the outcome of an operation to the configured metrics, if any.
+func (sdk *TwiplaSDK) observeCall(op operation, start time.Time, resp *http.Response, err error) {
+	if sdk.metrics == nil {
+		return
+	}
+	status, class := errorStatus(resp, err), classifyError(err)
+	sdk.metrics.ObserveCall(CallMetrics{
+		Operation:  op.name,
+		Duration:   time.Since(start),
+		Status:     status,
+		ErrorClass: class,
+	})
+}
+
+// errorStatus returns the HTTP status code of a call's response, or the one carried by its error.
+func errorStatus(resp *http.Response, err error) int {
+	if err == nil {
+		if resp == nil {
+			return 0
+		}
+		return resp.StatusCode
+	}
+	var apiErr APIError
+	var httpErr HTTPError
+	switch {
+	case errors.As(err, &apiErr):
+		return apiErr.Status
+	case errors.As(err, &httpErr):
+		return httpErr.StatusCode
+	case errors.Is(err, ErrInvalidAccessToken):
+		return http.StatusUnauthorized
+	default:
+		return 0
+	}
+}
+
+func classifyError(err error) ErrorClass {
+	if err == nil {
+		return ErrorClassNone
+	}
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case errors.Is(err, ErrCircuitOpen):
+		return ErrorClassCircuitOpen
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return ErrorClassTimeout
+		}
+		return ErrorClassNetwork
+	}
+
+	switch status := errorStatus(nil, err); {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrorClassAuth
+	case status == http.StatusTooManyRequests:
+		return ErrorClassRateLimited
+	case status >= 500:
+		return ErrorClassServer
+	case status >= 400:
+		return ErrorClassClient
+	default:
+		return ErrorClassOther
+	}
+}
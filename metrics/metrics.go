@@ -0,0 +1,142 @@
+// Package metrics implements [twipla3as.Metrics] with counters and histograms exposed in the Prometheus text format,
+// so they can be scraped without adding a Prometheus dependency to the SDK.
+package metrics
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the call duration histogram buckets.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Collector records the calls made by an SDK instance. It is safe for concurrent use.
+//
+// It exposes the following metrics:
+//   - twipla3as_calls_total, a counter labelled by operation, status and error_class;
+//   - twipla3as_call_duration_seconds, a histogram labelled by operation.
+type Collector struct {
+	buckets []float64
+
+	mu        sync.Mutex
+	calls     map[callKey]uint64
+	durations map[string]*histogram
+}
+
+var _ twipla3as.Metrics = (*Collector)(nil)
+
+type callKey struct {
+	operation  string
+	status     int
+	errorClass twipla3as.ErrorClass
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewCollector creates a Collector whose histograms use the given bucket upper bounds, in seconds.
+// If no buckets are given, [DefaultBuckets] are used.
+func NewCollector(buckets ...float64) *Collector {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+	return &Collector{
+		buckets:   slices.Compact(buckets),
+		calls:     map[callKey]uint64{},
+		durations: map[string]*histogram{},
+	}
+}
+
+// ObserveCall implements [twipla3as.Metrics].
+func (c *Collector) ObserveCall(call twipla3as.CallMetrics) {
+	seconds := call.Duration.Seconds()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls[callKey{operation: call.Operation, status: call.Status, errorClass: call.ErrorClass}]++
+	h, ok := c.durations[call.Operation]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(c.buckets))}
+		c.durations[call.Operation] = h
+	}
+	for i, bound := range c.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+// Its output can be appended to the one of an existing /metrics endpoint.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	calls := make([]callKey, 0, len(c.calls))
+	for key := range c.calls {
+		calls = append(calls, key)
+	}
+	slices.SortFunc(calls, func(a, b callKey) int {
+		return cmp.Or(
+			strings.Compare(a.operation, b.operation),
+			cmp.Compare(a.status, b.status),
+			strings.Compare(string(a.errorClass), string(b.errorClass)),
+		)
+	})
+	operations := make([]string, 0, len(c.durations))
+	for op := range c.durations {
+		operations = append(operations, op)
+	}
+	slices.Sort(operations)
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "# HELP twipla3as_calls_total Number of calls made to the TWIPLA 3AS API.")
+	fmt.Fprintln(&buf, "# TYPE twipla3as_calls_total counter")
+	for _, key := range calls {
+		fmt.Fprintf(&buf, "twipla3as_calls_total{operation=%s,status=%s,error_class=%s} %d\n",
+			quote(key.operation), quote(strconv.Itoa(key.status)), quote(string(key.errorClass)), c.calls[key])
+	}
+	fmt.Fprintln(&buf, "# HELP twipla3as_call_duration_seconds Duration of the calls made to the TWIPLA 3AS API, including retries.")
+	fmt.Fprintln(&buf, "# TYPE twipla3as_call_duration_seconds histogram")
+	for _, op := range operations {
+		h := c.durations[op]
+		for i, bound := range c.buckets {
+			fmt.Fprintf(&buf, "twipla3as_call_duration_seconds_bucket{operation=%s,le=%s} %d\n",
+				quote(op), quote(strconv.FormatFloat(bound, 'g', -1, 64)), h.counts[i])
+		}
+		fmt.Fprintf(&buf, "twipla3as_call_duration_seconds_bucket{operation=%s,le=\"+Inf\"} %d\n", quote(op), h.count)
+		fmt.Fprintf(&buf, "twipla3as_call_duration_seconds_sum{operation=%s} %s\n", quote(op), strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(&buf, "twipla3as_call_duration_seconds_count{operation=%s} %d\n", quote(op), h.count)
+	}
+	c.mu.Unlock()
+
+	return buf.WriteTo(w)
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = c.WriteTo(w)
+}
+
+// quote quotes a label value as expected by the Prometheus text format.
+func quote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
@@ -0,0 +1,47 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+	"github.com/twipla/3as-go-sdk/metrics"
+)
+
+func TestCollector(t *testing.T) {
+	c := metrics.NewCollector(0.1, 1)
+	c.ObserveCall(twipla3as.CallMetrics{Operation: "Website", Duration: 50 * time.Millisecond, Status: http.StatusOK})
+	c.ObserveCall(twipla3as.CallMetrics{Operation: "Website", Duration: 500 * time.Millisecond, Status: http.StatusOK})
+	c.ObserveCall(twipla3as.CallMetrics{
+		Operation:  "CreateINTPC",
+		Duration:   2 * time.Second,
+		Status:     http.StatusBadGateway,
+		ErrorClass: twipla3as.ErrorClassServer,
+	})
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, strings.Join([]string{
+		`# HELP twipla3as_calls_total Number of calls made to the TWIPLA 3AS API.`,
+		`# TYPE twipla3as_calls_total counter`,
+		`twipla3as_calls_total{operation="CreateINTPC",status="502",error_class="server"} 1`,
+		`twipla3as_calls_total{operation="Website",status="200",error_class=""} 2`,
+		`# HELP twipla3as_call_duration_seconds Duration of the calls made to the TWIPLA 3AS API, including retries.`,
+		`# TYPE twipla3as_call_duration_seconds histogram`,
+		`twipla3as_call_duration_seconds_bucket{operation="CreateINTPC",le="0.1"} 0`,
+		`twipla3as_call_duration_seconds_bucket{operation="CreateINTPC",le="1"} 0`,
+		`twipla3as_call_duration_seconds_bucket{operation="CreateINTPC",le="+Inf"} 1`,
+		`twipla3as_call_duration_seconds_sum{operation="CreateINTPC"} 2`,
+		`twipla3as_call_duration_seconds_count{operation="CreateINTPC"} 1`,
+		`twipla3as_call_duration_seconds_bucket{operation="Website",le="0.1"} 1`,
+		`twipla3as_call_duration_seconds_bucket{operation="Website",le="1"} 2`,
+		`twipla3as_call_duration_seconds_bucket{operation="Website",le="+Inf"} 2`,
+		`twipla3as_call_duration_seconds_sum{operation="Website"} 0.55`,
+		`twipla3as_call_duration_seconds_count{operation="Website"} 2`,
+	}, "\n")+"\n", rec.Body.String())
+}
@@ -0,0 +1,54 @@
+package twipla3as_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+type testMetrics []twipla3as.CallMetrics
+
+func (m *testMetrics) ObserveCall(call twipla3as.CallMetrics) {
+	*m = append(*m, call)
+}
+
+func TestMetrics(t *testing.T) {
+	var calls testMetrics
+	sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+		switch r.Method {
+		case http.MethodGet:
+			return jsonResponse(r, http.StatusOK, `{"payload":[]}`), nil
+		case http.MethodPost:
+			return jsonResponse(r, http.StatusTooManyRequests, `{"status":429,"message":"slow down"}`), nil
+		default:
+			return nil, errors.New("connection refused")
+		}
+	}, fastRetries(1, false), func(c *twipla3as.TwiplaConfig) {
+		c.Metrics = &calls
+	})
+
+	_, err := sdk.Packages(t.Context())
+	assert.NoError(t, err)
+	_, err = sdk.CreatePackage(t.Context(), twipla3as.CreatePackageArgs{Name: "package", Currency: twipla3as.CurrencyEUR, Period: twipla3as.PeriodMonthly})
+	assert.Error(t, err)
+	_, err = sdk.DeleteINTPC(t.Context(), "intpc-id")
+	assert.Error(t, err)
+
+	if assert.Len(t, calls, 3) {
+		assert.Equal(t, "Packages", calls[0].Operation)
+		assert.Equal(t, http.StatusOK, calls[0].Status)
+		assert.Equal(t, twipla3as.ErrorClassNone, calls[0].ErrorClass)
+		assert.Positive(t, calls[0].Duration)
+
+		assert.Equal(t, "CreatePackage", calls[1].Operation)
+		assert.Equal(t, http.StatusTooManyRequests, calls[1].Status)
+		assert.Equal(t, twipla3as.ErrorClassRateLimited, calls[1].ErrorClass)
+
+		assert.Equal(t, "DeleteINTPC", calls[2].Operation)
+		assert.Equal(t, 0, calls[2].Status)
+		assert.Equal(t, twipla3as.ErrorClassNetwork, calls[2].ErrorClass)
+	}
+}
@@ -0,0 +1,168 @@
+package twipla3as
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Handler sends an API request and returns its response.
+type Handler func(r *http.Request) (*http.Response, error)
+
+// Middleware wraps the [Handler] sending API requests, to act on every outgoing request and on its response or error.
+// A Middleware can short-circuit the chain by returning without calling next.
+//
+// The SDK's own behavior is implemented as links of the same chain. From the outermost to the innermost, a request goes through:
+//   - logging, if [TwiplaConfig.Logger] is set;
+//   - error decoding, which turns error responses into [APIError] and similar errors;
+//   - retries, as configured by [TwiplaConfig.Retry]; the links below run once per attempt;
+//   - authentication, which sets the INTP bearer token in the Authorization header;
+//   - the middleware registered with [TwiplaConfig.Middleware] and [TwiplaSDK.Use], in order;
+//   - rate limiting, as configured by [TwiplaConfig.RateLimit];
+//   - the circuit breaker, as configured by [TwiplaConfig.CircuitBreaker];
+//   - the configured [http.Client].
+//
+// Registered middleware thus sees authenticated requests and raw responses, including error ones.
+type Middleware func(next Handler) Handler
+
+// Use appends middleware to the chain wrapping every API call made by the SDK.
+// Calls that are already in flight keep using the previous chain.
+func (sdk *TwiplaSDK) Use(middleware ...Middleware) {
+	sdk.mu.Lock()
+	defer sdk.mu.Unlock()
+	sdk.middleware = append(sdk.middleware[:len(sdk.middleware):len(sdk.middleware)], middleware...)
+	sdk.buildHandler()
+}
+
+// buildHandler assembles the middleware chain. sdk.mu must be held, unless the SDK is being constructed.
+func (sdk *TwiplaSDK) buildHandler() {
+	var links []Middleware
+	if sdk.logger != nil {
+		links = append(links, sdk.logged)
+	}
+	links = append(links, sdk.decodeErrors, sdk.retrying, sdk.authorize)
+	links = append(links, sdk.middleware...)
+	links = append(links, sdk.rateLimited)
+	if sdk.breaker != nil {
+		links = append(links, sdk.circuitBroken)
+	}
+
+	handler := Handler(sdk.client.Do)
+	for i := len(links) - 1; i >= 0; i-- {
+		handler = links[i](handler)
+	}
+	sdk.handler.Store(&handler)
+}
+
+// decodeErrors turns error responses into errors, consuming their body.
+// Requests refused with [ErrInvalidAccessToken] are sent again signed with the previous keys of the SDK's [KeyRing],
+// as the API gateway may not know a freshly rotated key yet.
+func (sdk *TwiplaSDK) decodeErrors(next Handler) Handler {
+	return func(r *http.Request) (*http.Response, error) {
+		state := callStateFrom(r.Context())
+		tried := map[int]bool{}
+		for {
+			resp, err := next(r)
+			if err != nil {
+				return nil, err
+			}
+			err = decodeError(resp)
+			if err == nil {
+				return resp, nil
+			}
+			if !errors.Is(err, ErrInvalidAccessToken) {
+				return nil, err
+			}
+
+			var level int
+			if state != nil {
+				level = state.keyLevel
+			}
+			tried[level] = true
+			nextLevel, ok := sdk.signer.fallback(level)
+			if !ok || tried[nextLevel] || (r.Body != nil && r.Body != http.NoBody && r.GetBody == nil) {
+				return nil, err
+			}
+			r = r.Clone(r.Context())
+			if r.GetBody != nil {
+				if r.Body, err = r.GetBody(); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+}
+
+// retrying sends the request again after transient failures, as allowed by the SDK's retry policy.
+func (sdk *TwiplaSDK) retrying(next Handler) Handler {
+	return func(r *http.Request) (*http.Response, error) {
+		ctx := r.Context()
+		state := callStateFrom(ctx)
+		policy := sdk.retry
+		if state != nil && state.options.retry != nil {
+			policy = *state.options.retry
+		}
+		if state != nil && state.idempotent {
+			policy.RetryUnsafeMethods = true
+		}
+		for attempt := 1; ; attempt++ {
+			if state != nil {
+				state.attempts = attempt
+			}
+			req := r.Clone(ctx)
+			if attempt > 1 && r.GetBody != nil {
+				body, err := r.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				req.Body = body
+			}
+
+			resp, err := next(req)
+			if state != nil {
+				state.response = resp
+			}
+			if attempt >= policy.MaxAttempts || !policy.shouldRetry(ctx, r.Method, resp, err) {
+				return resp, err
+			}
+			delay, ok := policy.backoff(attempt, resp)
+			if !ok {
+				return resp, err
+			}
+			if resp != nil {
+				discard(resp)
+			}
+			if err := sleep(ctx, delay); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// authorize sets the INTP bearer token on the request.
+func (sdk *TwiplaSDK) authorize(next Handler) Handler {
+	return func(r *http.Request) (*http.Response, error) {
+		state := callStateFrom(r.Context())
+		token, level, err := sdk.signer.intpToken(r.Context(), state == nil || !state.options.noTokenCache)
+		if err != nil {
+			return nil, fmt.Errorf("can't sign bearer intp token: %w", err)
+		}
+		if state != nil {
+			state.keyLevel = level
+		}
+		r.Header.Set("Authorization", "Bearer "+token)
+		return next(r)
+	}
+}
+
+// rateLimited waits for the client-side rate limiter before sending the request, and adapts it to the response.
+func (sdk *TwiplaSDK) rateLimited(next Handler) Handler {
+	return func(r *http.Request) (*http.Response, error) {
+		if err := sdk.limiter.wait(r.Context(), r.Method); err != nil {
+			return nil, err
+		}
+		resp, err := next(r)
+		sdk.limiter.observe(r.Method, resp)
+		return resp, err
+	}
+}
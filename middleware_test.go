@@ -0,0 +1,60 @@
+package twipla3as_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Run("requests and responses are visible", func(t *testing.T) {
+		var statuses []int
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			assert.Equal(t, "tenant-1", r.Header.Get("X-Tenant"))
+			return jsonResponse(r, http.StatusNotFound, `{"status":404,"message":"not found"}`), nil
+		}, func(c *twipla3as.TwiplaConfig) {
+			c.Middleware = []twipla3as.Middleware{func(next twipla3as.Handler) twipla3as.Handler {
+				return func(r *http.Request) (*http.Response, error) {
+					assert.Contains(t, r.Header.Get("Authorization"), "Bearer ")
+					r.Header.Set("X-Tenant", "tenant-1")
+					resp, err := next(r)
+					if resp != nil {
+						statuses = append(statuses, resp.StatusCode)
+					}
+					return resp, err
+				}
+			}}
+		})
+
+		_, err := sdk.INTPC(t.Context(), "intpc-id")
+		var apiErr twipla3as.APIError
+		assert.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, []int{http.StatusNotFound}, statuses)
+	})
+
+	t.Run("middleware can short-circuit", func(t *testing.T) {
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			t.Fatal("request should not reach the transport")
+			return nil, nil
+		})
+		var order []string
+		for _, name := range []string{"first", "second"} {
+			sdk.Use(func(next twipla3as.Handler) twipla3as.Handler {
+				return func(r *http.Request) (*http.Response, error) {
+					order = append(order, name)
+					if name == "second" {
+						return jsonResponse(r, http.StatusOK, `{"payload":["twipla.com"]}`), nil
+					}
+					return next(r)
+				}
+			})
+		}
+
+		domains, err := sdk.WhitelistedDomains(t.Context(), "website-id")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"twipla.com"}, domains)
+		assert.Equal(t, []string{"first", "second"}, order)
+	})
+}
@@ -0,0 +1,109 @@
+package twipla3as
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidID is matched by the [ValidationError] returned, before any request is made, for IDs that can never be valid,
+// such as empty strings or dot segments. Other IDs are escaped, so they are always sent as a single path segment.
+var ErrInvalidID = errors.New("invalid ID")
+
+// operation describes a call made by a public SDK method to the 3AS API.
+type operation struct {
+	// name is the name of the SDK method, such as "CreateINTPC".
+	name   string
+	method string
+	// route is the path template of the endpoint, with one {placeholder} per ID-bearing segment,
+	// such as "/v2/3as/websites/{websiteId}/api-keys".
+	route string
+	// params holds the values of the route's placeholders, in order.
+	params []string
+	// rawPath is the escaped path of operations whose route is unknown, such as the ones of [TwiplaSDK.Do].
+	rawPath string
+
+	// intpcID and websiteID are the INTP's IDs of the customer and website concerned by the operation, if known.
+	intpcID   string
+	websiteID string
+}
+
+func newOperation(name string, method string, route string, params ...string) operation {
+	op := operation{
+		name:   name,
+		method: method,
+		route:  route,
+		params: params,
+	}
+	i := 0
+	for segment := range strings.SplitSeq(route, "/") {
+		if !isPlaceholder(segment) {
+			continue
+		}
+		if i < len(params) {
+			switch segment {
+			case "{intpcId}":
+				op.intpcID = params[i]
+			case "{websiteId}":
+				op.websiteID = params[i]
+			}
+		}
+		i++
+	}
+	return op
+}
+
+// rawRoute is the route reported for operations whose route is unknown, as their path may contain IDs.
+const rawRoute = "/{path}"
+
+// newRawOperation describes a call to an arbitrary, already escaped path.
+func newRawOperation(name string, method string, path string) operation {
+	return operation{
+		name:    name,
+		method:  method,
+		route:   rawRoute,
+		rawPath: path,
+	}
+}
+
+// forIntpc sets the ID of the customer concerned by an operation whose route does not include it.
+func (op operation) forIntpc(intpcID string) operation {
+	op.intpcID = intpcID
+	return op
+}
+
+// forWebsite sets the ID of the website concerned by an operation whose route does not include it.
+func (op operation) forWebsite(websiteID string) operation {
+	op.websiteID = websiteID
+	return op
+}
+
+// path expands the route's placeholders with the operation's params, each escaped as a single path segment.
+// IDs that can never be valid, such as empty strings or dot segments, are reported as a [ValidationError] matching [ErrInvalidID].
+func (op operation) path() (string, error) {
+	if op.rawPath != "" {
+		return op.rawPath, nil
+	}
+	var v validator
+	segments := strings.Split(op.route, "/")
+	i := 0
+	for j, segment := range segments {
+		if isPlaceholder(segment) && i < len(op.params) {
+			param := op.params[i]
+			name := strings.Trim(segment, "{}")
+			v.required(name, param)
+			v.check(param != "." && param != "..", name, "must not be a dot segment")
+			segments[j] = url.PathEscape(param)
+			i++
+		}
+	}
+	if len(v.err.Fields) > 0 {
+		v.cause(ErrInvalidID)
+		return "", v.result()
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+func isPlaceholder(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
@@ -0,0 +1,53 @@
+package twipla3as_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+func TestPathEscaping(t *testing.T) {
+	t.Run("IDs are sent as a single segment", func(t *testing.T) {
+		for id, escaped := range map[string]string{
+			"plain-id":       "/v2/3as/websites/plain-id",
+			"a/b":            "/v2/3as/websites/a%2Fb",
+			"../intpcs":      "/v2/3as/websites/..%2Fintpcs",
+			"id?page=2":      "/v2/3as/websites/id%3Fpage=2",
+			"100%":           "/v2/3as/websites/100%25",
+			"with space#tag": "/v2/3as/websites/with%20space%23tag",
+		} {
+			sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+				assert.Equal(t, escaped, r.URL.EscapedPath(), id)
+				assert.Empty(t, r.URL.RawQuery, id)
+				return jsonResponse(r, http.StatusOK, `{"payload":{"intpWebsiteId":"x"}}`), nil
+			})
+
+			_, err := sdk.Website(t.Context(), id)
+			assert.NoError(t, err, id)
+		}
+	})
+
+	t.Run("invalid IDs are rejected", func(t *testing.T) {
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			t.Fatal("invalid IDs should not reach the API")
+			return nil, nil
+		})
+
+		for _, id := range []string{"", ".", ".."} {
+			_, err := sdk.DeleteINTPC(t.Context(), id)
+			assert.ErrorIs(t, err, twipla3as.ErrInvalidID, id)
+			assert.ErrorIs(t, err, twipla3as.ErrValidation, id)
+		}
+
+		err := sdk.DeleteWebsiteApiKey(t.Context(), "..", "")
+		var validationErr *twipla3as.ValidationError
+		if assert.ErrorAs(t, err, &validationErr) {
+			assert.Equal(t, []twipla3as.FieldError{
+				{Field: "websiteId", Reason: "must not be a dot segment"},
+				{Field: "apiKeyId", Reason: "is required"},
+			}, validationErr.Fields)
+		}
+	})
+}
@@ -4,7 +4,6 @@ import (
 	"cmp"
 	"context"
 	"net/http"
-	"path"
 	"slices"
 	"time"
 )
@@ -37,7 +36,7 @@ type Package struct {
 }
 
 func (sdk *TwiplaSDK) Packages(ctx context.Context) ([]Package, error) {
-	resp, err := parseResponse[[]Package](sdk.apiCall(ctx, http.MethodGet, "/v2/3as/packages", nil))
+	resp, err := parseResponse[[]Package](sdk.apiCall(ctx, newOperation("Packages", http.MethodGet, "/v2/3as/packages"), nil))
 	if err != nil {
 		return nil, err
 	}
@@ -48,7 +47,7 @@ func (sdk *TwiplaSDK) Packages(ctx context.Context) ([]Package, error) {
 }
 
 func (sdk *TwiplaSDK) Package(ctx context.Context, packageID string) (Package, error) {
-	resp, err := parseResponse[Package](sdk.apiCall(ctx, http.MethodGet, path.Join("/v2/3as/packages", packageID), nil))
+	resp, err := parseResponse[Package](sdk.apiCall(ctx, newOperation("Package", http.MethodGet, "/v2/3as/packages/{packageId}", packageID), nil))
 	if err != nil {
 		return Package{}, err
 	}
@@ -64,7 +63,10 @@ type CreatePackageArgs struct {
 }
 
 func (sdk *TwiplaSDK) CreatePackage(ctx context.Context, args CreatePackageArgs) (Package, error) {
-	resp, err := parseResponse[Package](sdk.apiCall(ctx, http.MethodPost, "/v2/3as/packages", args))
+	if err := args.Validate(); err != nil {
+		return Package{}, err
+	}
+	resp, err := parseResponse[Package](sdk.apiCall(ctx, newOperation("CreatePackage", http.MethodPost, "/v2/3as/packages"), args))
 	if err != nil {
 		return Package{}, err
 	}
@@ -76,7 +78,10 @@ type UpdatePackageArgs struct {
 }
 
 func (sdk *TwiplaSDK) UpdatePackage(ctx context.Context, packageID string, args UpdatePackageArgs) (Package, error) {
-	resp, err := parseResponse[Package](sdk.apiCall(ctx, http.MethodPatch, path.Join("/v2/3as/packages", packageID), args))
+	if err := args.Validate(); err != nil {
+		return Package{}, err
+	}
+	resp, err := parseResponse[Package](sdk.apiCall(ctx, newOperation("UpdatePackage", http.MethodPatch, "/v2/3as/packages/{packageId}", packageID), args))
 	if err != nil {
 		return Package{}, err
 	}
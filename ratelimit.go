@@ -0,0 +1,152 @@
+package twipla3as
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimit configures a client-side token bucket limiter, shared by all the calls made through an SDK instance.
+// Reads (GET requests) and writes (every other method) have separate budgets.
+//
+// When the API answers with 429 Too Many Requests, the limiter halves the rate of the affected budget
+// and pauses it for the duration given by Retry-After, if any. The rate then recovers gradually with every successful call.
+type RateLimit struct {
+	// ReadsPerSecond is the sustained rate of read requests. Zero means reads are not limited.
+	ReadsPerSecond float64
+	// ReadBurst is the number of read requests that can be made at once. It defaults to 1.
+	ReadBurst int
+	// WritesPerSecond is the sustained rate of write requests. Zero means writes are not limited.
+	WritesPerSecond float64
+	// WriteBurst is the number of write requests that can be made at once. It defaults to 1.
+	WriteBurst int
+}
+
+type rateLimiter struct {
+	read  *tokenBucket
+	write *tokenBucket
+}
+
+func newRateLimiter(config *RateLimit) *rateLimiter {
+	if config == nil {
+		return nil
+	}
+	return &rateLimiter{
+		read:  newTokenBucket(config.ReadsPerSecond, config.ReadBurst),
+		write: newTokenBucket(config.WritesPerSecond, config.WriteBurst),
+	}
+}
+
+func (l *rateLimiter) bucket(method string) *tokenBucket {
+	if l == nil {
+		return nil
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return l.read
+	}
+	return l.write
+}
+
+// wait blocks until a request with the given method is allowed to be sent.
+func (l *rateLimiter) wait(ctx context.Context, method string) error {
+	return l.bucket(method).wait(ctx)
+}
+
+// observe adapts the limiter to the response of a request.
+func (l *rateLimiter) observe(method string, resp *http.Response) {
+	b := l.bucket(method)
+	if b == nil || resp == nil {
+		return
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter, _ := parseRetryAfter(resp.Header.Get("Retry-After"))
+		b.throttle(retryAfter)
+	} else if resp.StatusCode < 400 {
+		b.recover()
+	}
+}
+
+const (
+	// minRateFactor is the lowest fraction of the configured rate that 429 responses can lower a bucket to.
+	minRateFactor = 1.0 / 16
+	// recoverFactor is the fraction of the configured rate regained after every successful request.
+	recoverFactor = 1.0 / 10
+)
+
+type tokenBucket struct {
+	mu sync.Mutex
+	// limit is the configured rate, in tokens per second.
+	limit float64
+	// rate is the current rate, which is lower than limit after being throttled.
+	rate  float64
+	burst float64
+	// tokens is the number of available tokens. It is negative when requests are waiting for tokens.
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	burst = max(burst, 1)
+	return &tokenBucket{
+		limit:  rate,
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// refill adds the tokens accumulated since the last update. b.mu must be held.
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = min(b.tokens+now.Sub(b.last).Seconds()*b.rate, b.burst)
+	b.last = now
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	now := time.Now()
+	b.refill(now)
+	b.tokens--
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	delay = max(delay, b.pausedUntil.Sub(now))
+	b.mu.Unlock()
+
+	if err := sleep(ctx, delay); err != nil {
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (b *tokenBucket) throttle(retryAfter time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.refill(now)
+	b.rate = max(b.rate/2, b.limit*minRateFactor)
+	if until := now.Add(retryAfter); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+func (b *tokenBucket) recover() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.rate < b.limit {
+		b.refill(time.Now())
+		b.rate = min(b.rate+b.limit*recoverFactor, b.limit)
+	}
+}
@@ -0,0 +1,66 @@
+package twipla3as_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+func TestRateLimit(t *testing.T) {
+	rateLimited := func(c *twipla3as.TwiplaConfig) {
+		c.RateLimit = &twipla3as.RateLimit{
+			ReadsPerSecond: 20,
+			ReadBurst:      2,
+		}
+	}
+
+	t.Run("reads are limited", func(t *testing.T) {
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(r, http.StatusOK, `{"payload":[]}`), nil
+		}, rateLimited)
+
+		start := time.Now()
+		for range 6 {
+			_, err := sdk.WhitelistedDomains(t.Context(), "website-id")
+			assert.NoError(t, err)
+		}
+		// 2 requests are covered by the burst, the other 4 need to wait 50ms each.
+		assert.GreaterOrEqual(t, time.Since(start), 190*time.Millisecond)
+	})
+
+	t.Run("writes have a separate budget", func(t *testing.T) {
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(r, http.StatusOK, `{"payload":null}`), nil
+		}, rateLimited)
+
+		start := time.Now()
+		for range 6 {
+			assert.NoError(t, sdk.AddWebsiteWhitelistedDomain(t.Context(), "website-id", "twipla.com"))
+		}
+		assert.Less(t, time.Since(start), 100*time.Millisecond)
+	})
+
+	t.Run("429 responses slow down the limiter", func(t *testing.T) {
+		var calls int
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				resp := jsonResponse(r, http.StatusTooManyRequests, `{"status":429,"message":"slow down"}`)
+				resp.Header.Set("Retry-After", "1")
+				return resp, nil
+			}
+			return jsonResponse(r, http.StatusOK, `{"payload":[]}`), nil
+		}, rateLimited, fastRetries(1, false))
+
+		_, err := sdk.WhitelistedDomains(t.Context(), "website-id")
+		assert.Error(t, err)
+
+		start := time.Now()
+		_, err = sdk.WhitelistedDomains(t.Context(), "website-id")
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+	})
+}
@@ -0,0 +1,260 @@
+package twipla3as
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Recorder captures the requests sent by the SDK and the responses received, for debugging integrations.
+// It is attached to an SDK instance as a middleware:
+//
+//	rec := twipla3as.NewHARRecorder(file)
+//	sdk.Use(rec.Middleware())
+//	defer rec.Close()
+//
+// Every attempt of every call is recorded. Bearer tokens, INTPC tokens and API key secrets are always redacted.
+type Recorder struct {
+	w     io.Writer
+	jsonl bool
+
+	mu      sync.Mutex
+	entries []harEntry
+	closed  bool
+}
+
+// NewHARRecorder creates a [Recorder] writing a HAR 1.2 document to w when it is closed.
+func NewHARRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w}
+}
+
+// NewJSONLRecorder creates a [Recorder] writing every exchange to w as soon as it completes, as a HAR entry on its own line.
+func NewJSONLRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w, jsonl: true}
+}
+
+// ErrRecorderClosed is returned when recording an exchange after the [Recorder] was closed.
+var ErrRecorderClosed = errors.New("recorder is closed")
+
+// Middleware returns the [Middleware] recording the exchanges of the SDK it is added to.
+// Recording errors never fail the calls.
+func (rec *Recorder) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(r *http.Request) (*http.Response, error) {
+			start := time.Now()
+			request := recordRequest(r)
+			resp, err := next(r)
+			entry := harEntry{
+				StartedDateTime: start.Format(time.RFC3339Nano),
+				Time:            float64(time.Since(start).Microseconds()) / 1000,
+				Request:         request,
+				Response:        harResponse{HTTPVersion: "HTTP/1.1", Headers: []harNameValue{}, Cookies: []harNameValue{}, HeadersSize: -1, BodySize: -1},
+				Cache:           struct{}{},
+			}
+			entry.Timings.Wait = entry.Time
+			if err != nil {
+				entry.Error = err.Error()
+			} else if resp != nil {
+				entry.Response = recordResponse(resp)
+			}
+			_ = rec.record(entry)
+			return resp, err
+		}
+	}
+}
+
+func (rec *Recorder) record(entry harEntry) error {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.closed {
+		return ErrRecorderClosed
+	}
+	if !rec.jsonl {
+		rec.entries = append(rec.entries, entry)
+		return nil
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = rec.w.Write(append(data, '\n'))
+	return err
+}
+
+// Close stops recording. A HAR recorder writes its document on Close.
+func (rec *Recorder) Close() error {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.closed {
+		return nil
+	}
+	rec.closed = true
+	if rec.jsonl {
+		return nil
+	}
+	var doc harDocument
+	doc.Log.Version = "1.2"
+	doc.Log.Creator.Name = "twipla-3as-go-sdk"
+	doc.Log.Creator.Version = "1"
+	doc.Log.Entries = slices.Clip(rec.entries)
+	if doc.Log.Entries == nil {
+		doc.Log.Entries = []harEntry{}
+	}
+	enc := json.NewEncoder(rec.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+func recordRequest(r *http.Request) harRequest {
+	request := harRequest{
+		Method:      r.Method,
+		URL:         redactURL(r.URL.String()),
+		HTTPVersion: "HTTP/1.1",
+		Headers:     harHeaders(redactHeader(r.Header)),
+		QueryString: []harNameValue{},
+		Cookies:     []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    0,
+	}
+	query, _ := url.ParseQuery(redactQuery(r.URL.RawQuery))
+	for name, values := range query {
+		for _, value := range values {
+			request.QueryString = append(request.QueryString, harNameValue{Name: name, Value: value})
+		}
+	}
+	slices.SortFunc(request.QueryString, compareNameValues)
+	if r.GetBody != nil {
+		if body, err := r.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			_ = body.Close()
+			request.BodySize = len(data)
+			request.PostData = &harPostData{
+				MimeType: r.Header.Get("Content-Type"),
+				Text:     string(redactJSON(data)),
+			}
+		}
+	}
+	return request
+}
+
+// recordResponse records resp, replacing its body so that it can still be read by the SDK.
+func recordResponse(resp *http.Response) harResponse {
+	data, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), errReader{err}))
+	return harResponse{
+		Status:      resp.StatusCode,
+		StatusText:  http.StatusText(resp.StatusCode),
+		HTTPVersion: resp.Proto,
+		Headers:     harHeaders(redactHeader(resp.Header)),
+		Cookies:     []harNameValue{},
+		Content: harContent{
+			Size:     len(data),
+			MimeType: resp.Header.Get("Content-Type"),
+			Text:     string(redactJSON(data)),
+		},
+		HeadersSize: -1,
+		BodySize:    len(data),
+	}
+}
+
+// errReader returns err, or io.EOF if it is nil, so that a body that failed to be read fails the same way once replayed.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	if r.err == nil {
+		return 0, io.EOF
+	}
+	return 0, r.err
+}
+
+func harHeaders(h http.Header) []harNameValue {
+	headers := []harNameValue{}
+	for name, values := range h {
+		for _, value := range values {
+			headers = append(headers, harNameValue{Name: name, Value: value})
+		}
+	}
+	slices.SortFunc(headers, compareNameValues)
+	return headers
+}
+
+func compareNameValues(a, b harNameValue) int {
+	return cmp.Or(strings.Compare(a.Name, b.Name), strings.Compare(a.Value, b.Value))
+}
+
+type harDocument struct {
+	Log struct {
+		Version string `json:"version"`
+		Creator struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"creator"`
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         struct {
+		Send    float64 `json:"send"`
+		Wait    float64 `json:"wait"`
+		Receive float64 `json:"receive"`
+	} `json:"timings"`
+	// Error holds the error of exchanges that got no response. It is a custom field, as allowed by HAR.
+	Error string `json:"_error,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	Cookies     []harNameValue `json:"cookies"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	Cookies     []harNameValue `json:"cookies"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
@@ -0,0 +1,91 @@
+package twipla3as_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Run("HAR document is redacted", func(t *testing.T) {
+		var buf bytes.Buffer
+		rec := twipla3as.NewHARRecorder(&buf)
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(r, http.StatusCreated, `{"payload":{"id":"key-id","name":"ci","apiKey":"super-secret"}}`), nil
+		})
+		sdk.Use(rec.Middleware())
+
+		key, err := sdk.CreateWebsiteApiKey(t.Context(), twipla3as.CreateApiKeyArgs{ExternalWebsiteID: "website-id", Name: "ci"})
+		assert.NoError(t, err)
+		assert.Equal(t, "super-secret", *key.ApiKey, "the SDK still reads the recorded response")
+		assert.NoError(t, rec.Close())
+
+		var doc struct {
+			Log struct {
+				Version string `json:"version"`
+				Entries []struct {
+					Request struct {
+						Method   string `json:"method"`
+						URL      string `json:"url"`
+						PostData struct {
+							Text string `json:"text"`
+						} `json:"postData"`
+					} `json:"request"`
+					Response struct {
+						Status  int `json:"status"`
+						Content struct {
+							Text string `json:"text"`
+						} `json:"content"`
+					} `json:"response"`
+				} `json:"entries"`
+			} `json:"log"`
+		}
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+		assert.Equal(t, "1.2", doc.Log.Version)
+		if assert.Len(t, doc.Log.Entries, 1) {
+			entry := doc.Log.Entries[0]
+			assert.Equal(t, http.MethodPost, entry.Request.Method)
+			assert.Equal(t, "https://api-gateway.va-endpoint.com/v2/3as/websites/website-id/api-keys", entry.Request.URL)
+			assert.JSONEq(t, `{"name":"ci"}`, entry.Request.PostData.Text)
+			assert.Equal(t, http.StatusCreated, entry.Response.Status)
+			assert.Contains(t, entry.Response.Content.Text, "[REDACTED]")
+		}
+		assert.NotContains(t, buf.String(), "super-secret")
+		assert.Contains(t, buf.String(), "Bearer [REDACTED]")
+		assert.NotContains(t, buf.String(), "eyJ", "no token is recorded")
+	})
+
+	t.Run("JSONL records every attempt", func(t *testing.T) {
+		var buf bytes.Buffer
+		rec := twipla3as.NewJSONLRecorder(&buf)
+		var calls int
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				return nil, syscall.ECONNRESET
+			}
+			return jsonResponse(r, http.StatusOK, `{"payload":["twipla.com"]}`), nil
+		}, fastRetries(2, false))
+		sdk.Use(rec.Middleware())
+
+		_, err := sdk.WhitelistedDomains(t.Context(), "website-id")
+		assert.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		if assert.Len(t, lines, 2) {
+			assert.Contains(t, lines[0], `"_error":`)
+			assert.True(t, strings.Contains(lines[1], `"status":200`), lines[1])
+		}
+
+		assert.NoError(t, rec.Close())
+		_, err = sdk.WhitelistedDomains(t.Context(), "website-id")
+		assert.NoError(t, err, "a closed recorder does not fail calls")
+		assert.Equal(t, 2, strings.Count(buf.String(), "\n"))
+	})
+}
@@ -0,0 +1,111 @@
+package twipla3as
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// redacted replaces secret values in logs and recordings.
+const redacted = "[REDACTED]"
+
+// secretQueryParams are the query parameters that carry secrets, such as the INTPC token in iframe URLs.
+var secretQueryParams = []string{"intpc_token"}
+
+// secretJSONFields are the JSON object keys whose values are secrets, such as [ApiKey.ApiKey].
+var secretJSONFields = []string{"apiKey", "intpc_token", "token", "privateKey"}
+
+// redactHeader returns a copy of h with the credentials removed.
+func redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	for _, name := range []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"} {
+		values := h.Values(name)
+		for i, v := range values {
+			if scheme, _, ok := strings.Cut(v, " "); ok && name != "Cookie" && name != "Set-Cookie" {
+				values[i] = scheme + " " + redacted
+			} else {
+				values[i] = redacted
+			}
+		}
+	}
+	return h
+}
+
+// redactURL returns rawURL with the values of secret query parameters replaced.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return redacted
+	}
+	u.RawQuery = redactQuery(u.RawQuery)
+	return u.String()
+}
+
+// redactQuery returns rawQuery with the values of secret query parameters replaced.
+func redactQuery(rawQuery string) string {
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return redacted
+	}
+	changed := false
+	for _, name := range secretQueryParams {
+		if query.Has(name) {
+			query.Set(name, redacted)
+			changed = true
+		}
+	}
+	if !changed {
+		return rawQuery
+	}
+	return query.Encode()
+}
+
+// redactJSON returns data with the values of secret fields replaced, at any depth.
+// Data that is not valid JSON is returned unchanged.
+func redactJSON(data []byte) []byte {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return data
+	}
+	if !redactValue(value) {
+		return data
+	}
+	clean, err := json.Marshal(value)
+	if err != nil {
+		return data
+	}
+	return clean
+}
+
+// redactValue replaces secrets within a decoded JSON value in place, and reports whether any were found.
+func redactValue(value any) bool {
+	changed := false
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if field != nil && isSecretField(key) {
+				v[key] = redacted
+				changed = true
+			} else if redactValue(field) {
+				changed = true
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if redactValue(item) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+func isSecretField(key string) bool {
+	for _, name := range secretJSONFields {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package twipla3as
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// ResponseMeta holds the metadata of an API response, such as the request ID to quote in support tickets.
+// It is available on errors through [APIError.Meta], and for any call through the [CaptureMeta] call option.
+type ResponseMeta struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// RequestID is the ID assigned to the request by the API gateway, if any.
+	RequestID string
+	// TraceID is the ID of the trace the request was part of on the API side, if any.
+	TraceID string
+	// RateLimit is the rate limit status advertised by the API, or nil if it did not advertise any.
+	RateLimit *RateLimitStatus
+	// Deprecation and Sunset hold the values of the Deprecation and Sunset headers, set when the endpoint is being phased out.
+	Deprecation string
+	Sunset      string
+	// Header holds all the response headers.
+	Header http.Header
+}
+
+// RateLimitStatus is the rate limit advertised by the API through the RateLimit-* or X-RateLimit-* headers.
+type RateLimitStatus struct {
+	// Limit is the number of requests allowed in the current window, or -1 if unknown.
+	Limit int
+	// Remaining is the number of requests left in the current window, or -1 if unknown.
+	Remaining int
+	// Reset is the raw value of the reset header, usually a number of seconds.
+	Reset string
+}
+
+var (
+	requestIDHeaders = []string{"X-Request-Id", "X-Amzn-RequestId", "X-Amz-Apigw-Id", "X-Correlation-Id"}
+	traceIDHeaders   = []string{"X-Amzn-Trace-Id", "Traceparent", "X-B3-TraceId", "X-Cloud-Trace-Context"}
+)
+
+func newResponseMeta(resp *http.Response) *ResponseMeta {
+	if resp == nil {
+		return nil
+	}
+	return &ResponseMeta{
+		StatusCode:  resp.StatusCode,
+		RequestID:   firstHeader(resp.Header, requestIDHeaders...),
+		TraceID:     firstHeader(resp.Header, traceIDHeaders...),
+		RateLimit:   parseRateLimitStatus(resp.Header),
+		Deprecation: resp.Header.Get("Deprecation"),
+		Sunset:      resp.Header.Get("Sunset"),
+		Header:      resp.Header.Clone(),
+	}
+}
+
+func parseRateLimitStatus(h http.Header) *RateLimitStatus {
+	limit := firstHeader(h, "RateLimit-Limit", "X-RateLimit-Limit")
+	remaining := firstHeader(h, "RateLimit-Remaining", "X-RateLimit-Remaining")
+	reset := firstHeader(h, "RateLimit-Reset", "X-RateLimit-Reset")
+	if limit == "" && remaining == "" && reset == "" {
+		return nil
+	}
+	return &RateLimitStatus{
+		Limit:     atoiOr(limit, -1),
+		Remaining: atoiOr(remaining, -1),
+		Reset:     reset,
+	}
+}
+
+func firstHeader(h http.Header, names ...string) string {
+	for _, name := range names {
+		if value := h.Get(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func atoiOr(s string, fallback int) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,50 @@
+package twipla3as_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+func TestResponseMeta(t *testing.T) {
+	sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+		var resp *http.Response
+		if r.Method == http.MethodDelete {
+			resp = jsonResponse(r, http.StatusForbidden, `{"status":403,"message":"forbidden","code":3}`)
+		} else {
+			resp = jsonResponse(r, http.StatusOK, `{"payload":[]}`)
+			resp.Header.Set("Deprecation", "true")
+			resp.Header.Set("Sunset", "Wed, 01 Jul 2026 00:00:00 GMT")
+		}
+		resp.Header.Set("X-Request-Id", "request-"+r.Method)
+		resp.Header.Set("X-Amzn-Trace-Id", "Root=1-abc")
+		resp.Header.Set("X-RateLimit-Limit", "100")
+		resp.Header.Set("X-RateLimit-Remaining", "99")
+		resp.Header.Set("X-RateLimit-Reset", "60")
+		return resp, nil
+	})
+
+	t.Run("successful calls", func(t *testing.T) {
+		var meta twipla3as.ResponseMeta
+		_, err := sdk.Packages(twipla3as.WithCallOptions(t.Context(), twipla3as.CaptureMeta(&meta)))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, meta.StatusCode)
+		assert.Equal(t, "request-GET", meta.RequestID)
+		assert.Equal(t, "Root=1-abc", meta.TraceID)
+		assert.Equal(t, &twipla3as.RateLimitStatus{Limit: 100, Remaining: 99, Reset: "60"}, meta.RateLimit)
+		assert.Equal(t, "true", meta.Deprecation)
+		assert.Equal(t, "Wed, 01 Jul 2026 00:00:00 GMT", meta.Sunset)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		err := sdk.DeleteWebsite(t.Context(), "website-id")
+		var apiErr twipla3as.APIError
+		if assert.ErrorAs(t, err, &apiErr) && assert.NotNil(t, apiErr.Meta) {
+			assert.Equal(t, http.StatusForbidden, apiErr.Meta.StatusCode)
+			assert.Equal(t, "request-DELETE", apiErr.Meta.RequestID)
+		}
+		assert.EqualError(t, err, "API error: 403 forbidden (Code: 3) (Request ID: request-DELETE)")
+	})
+}
@@ -0,0 +1,134 @@
+package twipla3as
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how API calls that failed with a transient error are retried.
+//
+// Requests are retried on temporary errors, as reported by [IsTemporary]: network errors and 429, 502, 503 and 504 responses.
+// By default, only safe methods (GET and DELETE) are retried, since retrying a POST or PATCH
+// such as [TwiplaSDK.CreateINTPC] or [TwiplaSDK.UpgradeWebsiteSubscription] might apply it twice.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a call, including the first one.
+	// Values lower than 2 disable retries.
+	MaxAttempts int
+	// MinBackoff is the base delay before the first retry. It doubles on every subsequent attempt.
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts. A Retry-After header sent by the API takes precedence over the
+	// exponential delay, but when it asks to wait longer than MaxBackoff, the call is not retried and its error is returned,
+	// so that the caller can reschedule it. Zero means no cap.
+	MaxBackoff time.Duration
+	// RetryUnsafeMethods opts non-idempotent requests (POST, PATCH) into retries.
+	RetryUnsafeMethods bool
+}
+
+// DefaultRetryPolicy is used when [TwiplaConfig.Retry] is nil.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  200 * time.Millisecond,
+	MaxBackoff:  5 * time.Second,
+}
+
+// RetryError is returned when a call failed after being attempted more than once.
+// Err holds the cause of the last failed attempt.
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("giving up after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+func (p RetryPolicy) allowsMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodDelete:
+		return true
+	default:
+		return p.RetryUnsafeMethods
+	}
+}
+
+// shouldRetry reports whether an attempt that resulted in resp or err can be retried.
+func (p RetryPolicy) shouldRetry(ctx context.Context, method string, resp *http.Response, err error) bool {
+	if !p.allowsMethod(method) || ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		// Retrying right away while the circuit breaker is open would only fail again.
+		return IsTemporary(err) && !errors.Is(err, ErrCircuitOpen)
+	}
+	return temporaryStatus(resp.StatusCode)
+}
+
+// backoff returns the delay to wait before the given (1-indexed) retry.
+// The exponential delay uses equal jitter, and is overridden by a Retry-After header, if present.
+// It reports false when the Retry-After header exceeds MaxBackoff, in which case the call must not be retried.
+func (p RetryPolicy) backoff(retry int, resp *http.Response) (time.Duration, bool) {
+	delay := p.MinBackoff
+	for i := 1; i < retry && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 {
+		delay = min(delay, p.MaxBackoff)
+	}
+	if delay > 0 {
+		delay = delay/2 + rand.N(delay/2+1)
+	}
+	if resp != nil {
+		if after, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if p.MaxBackoff > 0 && after > p.MaxBackoff {
+				return 0, false
+			}
+			delay = after
+		}
+	}
+	return delay, true
+}
+
+// parseRetryAfter parses a Retry-After header, given either in seconds or as an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// sleep waits for d, returning early with the context's error if it is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// discard drains and closes a response body that will not be read, so its connection can be reused.
+func discard(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,138 @@
+package twipla3as_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+func fastRetries(attempts int, unsafe bool) func(*twipla3as.TwiplaConfig) {
+	return func(c *twipla3as.TwiplaConfig) {
+		c.Retry = &twipla3as.RetryPolicy{
+			MaxAttempts:        attempts,
+			MinBackoff:         time.Millisecond,
+			MaxBackoff:         5 * time.Millisecond,
+			RetryUnsafeMethods: unsafe,
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	t.Run("transient errors are retried", func(t *testing.T) {
+		var calls int
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			calls++
+			switch calls {
+			case 1:
+				return nil, syscall.ECONNRESET
+			case 2:
+				return jsonResponse(r, http.StatusBadGateway, `{"status":502,"message":"bad gateway"}`), nil
+			default:
+				return jsonResponse(r, http.StatusOK, `{"payload":[]}`), nil
+			}
+		}, fastRetries(3, false))
+
+		_, err := sdk.Packages(t.Context())
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("final cause is reported", func(t *testing.T) {
+		var calls int
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			calls++
+			return jsonResponse(r, http.StatusServiceUnavailable, `{"status":503,"message":"unavailable"}`), nil
+		}, fastRetries(3, false))
+
+		_, err := sdk.Package(t.Context(), "package-id")
+		var retryErr *twipla3as.RetryError
+		assert.ErrorAs(t, err, &retryErr)
+		assert.Equal(t, 3, retryErr.Attempts)
+		var apiErr twipla3as.APIError
+		assert.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusServiceUnavailable, apiErr.Status)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("permanent errors are not retried", func(t *testing.T) {
+		var calls int
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			calls++
+			return jsonResponse(r, http.StatusNotFound, `{"status":404,"message":"not found"}`), nil
+		}, fastRetries(3, false))
+
+		_, err := sdk.Website(t.Context(), "website-id")
+		var retryErr *twipla3as.RetryError
+		assert.False(t, errors.As(err, &retryErr))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("unsafe methods need opt-in", func(t *testing.T) {
+		for _, unsafe := range []bool{false, true} {
+			var calls int
+			sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+				calls++
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.True(t, strings.Contains(string(body), `"packageId":"package-id"`))
+				return jsonResponse(r, http.StatusBadGateway, `{"status":502,"message":"bad gateway"}`), nil
+			}, fastRetries(2, unsafe))
+
+			err := sdk.UpgradeWebsiteSubscription(t.Context(), twipla3as.UpgradeWebsiteSubscriptionArgs{
+				WebsiteID: "website-id",
+				PackageID: "package-id",
+			})
+			assert.Error(t, err)
+			if unsafe {
+				assert.Equal(t, 2, calls)
+			} else {
+				assert.Equal(t, 1, calls)
+			}
+		}
+	})
+
+	t.Run("Retry-After is honored", func(t *testing.T) {
+		var calls int
+		var last time.Time
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				last = time.Now()
+				resp := jsonResponse(r, http.StatusTooManyRequests, `{"status":429,"message":"slow down"}`)
+				resp.Header.Set("Retry-After", "1")
+				return resp, nil
+			}
+			assert.GreaterOrEqual(t, time.Since(last), 900*time.Millisecond)
+			return jsonResponse(r, http.StatusOK, `{"payload":[]}`), nil
+		}, fastRetries(2, false), func(c *twipla3as.TwiplaConfig) {
+			c.Retry.MaxBackoff = 2 * time.Second
+		})
+
+		_, err := sdk.WhitelistedDomains(t.Context(), "website-id")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Retry-After beyond MaxBackoff is not waited for", func(t *testing.T) {
+		var calls int
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			calls++
+			resp := jsonResponse(r, http.StatusTooManyRequests, `{"status":429,"message":"slow down"}`)
+			resp.Header.Set("Retry-After", "86400")
+			return resp, nil
+		}, fastRetries(3, false))
+
+		start := time.Now()
+		_, err := sdk.WhitelistedDomains(t.Context(), "website-id")
+		assert.ErrorIs(t, err, twipla3as.ErrRateLimited)
+		assert.Equal(t, 1, calls)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
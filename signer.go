@@ -0,0 +1,160 @@
+package twipla3as
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Signer signs the JWTs authenticating the SDK to the 3AS API, both the INTP tokens sent with every call
+// and the INTPC tokens embedded in dashboard URLs.
+// It allows keeping the INTP private key outside of the application's memory, such as in a KMS or an HSM.
+// [NewCryptoSigner] adapts any [crypto.Signer].
+type Signer interface {
+	// SignJWT returns the compact serialization of a JWT holding claims, signed with the INTP's private key.
+	// Its "kid" header must be the ID under which the key is registered with TWIPLA, which defaults to the INTP ID
+	// (see [TwiplaConfig.KeyID]). Each key of a [KeyRing] sets its own.
+	SignJWT(ctx context.Context, claims map[string]any) (string, error)
+}
+
+// Algorithm is a JWT signing algorithm.
+type Algorithm string
+
+const (
+	// AlgorithmRS256 is RSASSA-PKCS1-v1_5 with SHA-256, the default for RSA keys.
+	AlgorithmRS256 Algorithm = "RS256"
+	// AlgorithmRS512 is RSASSA-PKCS1-v1_5 with SHA-512.
+	AlgorithmRS512 Algorithm = "RS512"
+	// AlgorithmPS256 is RSASSA-PSS with SHA-256.
+	AlgorithmPS256 Algorithm = "PS256"
+	// AlgorithmES256 is ECDSA with the P-256 curve and SHA-256, the default for ECDSA keys.
+	// It can only be used where the API gateway supports it.
+	AlgorithmES256 Algorithm = "ES256"
+	// AlgorithmEdDSA is Ed25519, the default for Ed25519 keys.
+	// It can only be used where the API gateway supports it.
+	AlgorithmEdDSA Algorithm = "EdDSA"
+)
+
+var (
+	// ErrUnsupportedKey is returned for private keys whose type cannot be used to sign tokens with the requested algorithm.
+	ErrUnsupportedKey = errors.New("unsupported private key")
+	// ErrUnsupportedAlgorithm is returned for unknown signing algorithms.
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+)
+
+// NewCryptoSigner returns a [Signer] signing tokens with key, such as a key held by a KMS, and setting their "kid" header to keyID.
+// It is also used for the in-memory key set by [TwiplaConfig.PrivateKey].
+// If alg is empty, the default algorithm for the type of key is used: RS256, ES256 or EdDSA.
+func NewCryptoSigner(key crypto.Signer, keyID string, alg Algorithm) (Signer, error) {
+	public := key.Public()
+	if alg == "" {
+		switch public.(type) {
+		case *rsa.PublicKey:
+			alg = AlgorithmRS256
+		case *ecdsa.PublicKey:
+			alg = AlgorithmES256
+		case ed25519.PublicKey:
+			alg = AlgorithmEdDSA
+		}
+	}
+
+	method, ok := signingMethods[alg]
+	if !ok {
+		if alg == "" {
+			return nil, fmt.Errorf("%w: %T", ErrUnsupportedKey, public)
+		}
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, alg)
+	}
+	var compatible bool
+	switch public := public.(type) {
+	case *rsa.PublicKey:
+		compatible = method.rsa
+	case *ecdsa.PublicKey:
+		compatible = alg == AlgorithmES256 && public.Curve == elliptic.P256()
+	case ed25519.PublicKey:
+		compatible = alg == AlgorithmEdDSA
+	}
+	if !compatible {
+		return nil, fmt.Errorf("%w: %T cannot sign %s tokens", ErrUnsupportedKey, public, alg)
+	}
+	return &cryptoSigner{key: key, keyID: keyID, method: method}, nil
+}
+
+type cryptoSigner struct {
+	key    crypto.Signer
+	keyID  string
+	method *cryptoSigningMethod
+}
+
+func (s *cryptoSigner) SignJWT(_ context.Context, claims map[string]any) (string, error) {
+	token := jwt.NewWithClaims(s.method, jwt.MapClaims(claims))
+	token.Header["kid"] = s.keyID
+	return token.SignedString(s.key)
+}
+
+// cryptoSigningMethod is a [jwt.SigningMethod] signing with a [crypto.Signer],
+// whose private key may not be available to the jwt package.
+type cryptoSigningMethod struct {
+	alg  Algorithm
+	hash crypto.Hash
+	// rsa is set for the algorithms signing with RSA keys, and pss for the ones using RSASSA-PSS.
+	rsa, pss bool
+}
+
+var signingMethods = map[Algorithm]*cryptoSigningMethod{
+	AlgorithmRS256: {alg: AlgorithmRS256, hash: crypto.SHA256, rsa: true},
+	AlgorithmRS512: {alg: AlgorithmRS512, hash: crypto.SHA512, rsa: true},
+	AlgorithmPS256: {alg: AlgorithmPS256, hash: crypto.SHA256, rsa: true, pss: true},
+	AlgorithmES256: {alg: AlgorithmES256, hash: crypto.SHA256},
+	AlgorithmEdDSA: {alg: AlgorithmEdDSA},
+}
+
+func (m *cryptoSigningMethod) Alg() string {
+	return string(m.alg)
+}
+
+func (m *cryptoSigningMethod) Sign(signingString string, key any) ([]byte, error) {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, jwt.ErrInvalidKeyType
+	}
+	if m.alg == AlgorithmEdDSA {
+		// Ed25519 signs the message itself rather than a digest.
+		return signer.Sign(rand.Reader, []byte(signingString), crypto.Hash(0))
+	}
+
+	h := m.hash.New()
+	h.Write([]byte(signingString))
+	var opts crypto.SignerOpts = m.hash
+	if m.pss {
+		opts = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: m.hash}
+	}
+	sig, err := signer.Sign(rand.Reader, h.Sum(nil), opts)
+	if err != nil || m.alg != AlgorithmES256 {
+		return sig, err
+	}
+
+	// crypto.Signer returns ASN.1 encoded ECDSA signatures, while JWTs hold the fixed size concatenation of r and s.
+	var ecSig struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(sig, &ecSig); err != nil {
+		return nil, err
+	}
+	out := make([]byte, 64)
+	ecSig.R.FillBytes(out[:32])
+	ecSig.S.FillBytes(out[32:])
+	return out, nil
+}
+
+func (m *cryptoSigningMethod) Verify(signingString string, sig []byte, key any) error {
+	return jwt.GetSigningMethod(string(m.alg)).Verify(signingString, sig, key)
+}
@@ -0,0 +1,91 @@
+package twipla3as_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+// remoteKey is a [crypto.Signer] whose private key is not accessible, like a key held by a KMS.
+type remoteKey struct {
+	key   *rsa.PrivateKey
+	calls int
+}
+
+func (k *remoteKey) Public() crypto.PublicKey {
+	return &k.key.PublicKey
+}
+
+func (k *remoteKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	k.calls++
+	return k.key.Sign(rand, digest, opts)
+}
+
+type signerFunc func(ctx context.Context, claims map[string]any) (string, error)
+
+func (f signerFunc) SignJWT(ctx context.Context, claims map[string]any) (string, error) {
+	return f(ctx, claims)
+}
+
+func TestSigner(t *testing.T) {
+	t.Run("crypto.Signer", func(t *testing.T) {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(t, err)
+		key := &remoteKey{key: rsaKey}
+		signer, err := twipla3as.NewCryptoSigner(key, "test-intp", "")
+		assert.NoError(t, err)
+
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			token, err := jwt.Parse(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), func(token *jwt.Token) (any, error) {
+				assert.Equal(t, "test-intp", token.Header["kid"])
+				return &rsaKey.PublicKey, nil
+			}, jwt.WithValidMethods([]string{"RS256"}))
+			assert.NoError(t, err)
+			assert.True(t, token.Valid)
+			return jsonResponse(r, http.StatusOK, `{"payload":[]}`), nil
+		}, func(c *twipla3as.TwiplaConfig) {
+			c.PrivateKey = ""
+			c.Signer = signer
+		})
+
+		_, err = sdk.Packages(t.Context())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, key.calls)
+	})
+
+	t.Run("signing errors", func(t *testing.T) {
+		errKMS := errors.New("kms unavailable")
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			t.Fatal("unsigned requests should not be sent")
+			return nil, nil
+		}, func(c *twipla3as.TwiplaConfig) {
+			c.Signer = signerFunc(func(ctx context.Context, claims map[string]any) (string, error) {
+				return "", errKMS
+			})
+		})
+
+		_, err := sdk.Packages(t.Context())
+		assert.ErrorIs(t, err, errKMS)
+		_, err = sdk.GenerateIframeURL("intpc-id", "website-id")
+		assert.ErrorIs(t, err, errKMS)
+	})
+
+	t.Run("unsupported keys", func(t *testing.T) {
+		key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		assert.NoError(t, err)
+		_, err = twipla3as.NewCryptoSigner(key, "test-intp", "")
+		assert.ErrorIs(t, err, twipla3as.ErrUnsupportedKey)
+	})
+}
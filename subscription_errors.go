@@ -0,0 +1,88 @@
+package twipla3as
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Reasons for which the API refuses a subscription transition, matched by [SubscriptionError] with [errors.Is].
+var (
+	// ErrNotAnUpgrade is returned when the new package of an upgrade does not have more touchpoints than the current one.
+	ErrNotAnUpgrade = errors.New("package is not an upgrade")
+	// ErrNotADowngrade is returned when the new package of a downgrade does not have fewer touchpoints than the current one.
+	ErrNotADowngrade = errors.New("package is not a downgrade")
+	// ErrPeriodMismatch is returned when the billing period of the new package differs from the one of the subscription.
+	ErrPeriodMismatch = errors.New("package period does not match the subscription")
+	// ErrSubscriptionCanceled is returned when the subscription is already canceled.
+	ErrSubscriptionCanceled = errors.New("subscription is already canceled")
+	// ErrWrongSubscriptionType is returned when the subscription type does not match the call,
+	// such as a Website subscription transition for a website of an INTPC with an INTPC subscription.
+	ErrWrongSubscriptionType = errors.New("wrong subscription type")
+)
+
+// SubscriptionError is returned when the API refuses a subscription transition for a known reason,
+// so that it can be explained to the customer.
+// Both Reason and the error returned by the API, usually an [APIError], can be matched with [errors.Is] and [errors.As].
+type SubscriptionError struct {
+	// Operation is the refused transition, such as "UpgradeWebsiteSubscription".
+	Operation string
+	// Reason is one of [ErrNotAnUpgrade], [ErrNotADowngrade], [ErrPeriodMismatch], [ErrSubscriptionCanceled] or [ErrWrongSubscriptionType].
+	Reason error
+	// Err is the error returned by the API.
+	Err error
+}
+
+func (e *SubscriptionError) Error() string {
+	return fmt.Sprintf("%s refused: %v: %v", e.Operation, e.Reason, e.Err)
+}
+
+func (e *SubscriptionError) Unwrap() []error {
+	return []error{e.Reason, e.Err}
+}
+
+// subscriptionRejections maps the messages recognized as refusing a transition to their reason.
+// TWIPLA does not document error codes or messages for these refusals, so the list is not derived from the API:
+// it must be checked against real responses, and extended, as they are observed. Only these exact messages are matched,
+// regardless of case and of a trailing period. Any other refusal is returned as is, rather than explained with a reason that may be wrong.
+var subscriptionRejections = []struct {
+	reason error
+	// prefix restricts the reason to the operations starting with it.
+	prefix   string
+	messages []string
+}{
+	{reason: ErrSubscriptionCanceled, messages: []string{
+		"subscription is already canceled",
+		"subscription is already cancelled",
+		"subscription was canceled",
+		"subscription was cancelled",
+	}},
+	{reason: ErrPeriodMismatch, messages: []string{"package period does not match the subscription period"}},
+	{reason: ErrWrongSubscriptionType, messages: []string{"invalid subscription type for this intp"}},
+	{reason: ErrNotAnUpgrade, prefix: "Upgrade", messages: []string{"new package must have more touchpoints than the current one"}},
+	{reason: ErrNotADowngrade, prefix: "Downgrade", messages: []string{"cannot downgrade to a package with higher touchpoints"}},
+}
+
+// subscriptionError turns err into a [SubscriptionError] when the API refused the transition op for a known reason.
+func subscriptionError(op operation, err error) error {
+	var apiErr APIError
+	if !errors.As(err, &apiErr) || apiErr.Status < 400 || apiErr.Status >= 500 ||
+		apiErr.Status == http.StatusUnauthorized || apiErr.Status == http.StatusNotFound {
+		return err
+	}
+	messages := []string{normalizeMessage(apiErr.Message), normalizeMessage(apiErr.OtherError)}
+	for _, rejection := range subscriptionRejections {
+		if strings.HasPrefix(op.name, rejection.prefix) && slices.ContainsFunc(messages, func(message string) bool {
+			return slices.Contains(rejection.messages, message)
+		}) {
+			return &SubscriptionError{Operation: op.name, Reason: rejection.reason, Err: err}
+		}
+	}
+	return err
+}
+
+func normalizeMessage(message string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(message)), ".")
+}
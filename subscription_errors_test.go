@@ -0,0 +1,66 @@
+package twipla3as_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+func TestSubscriptionErrors(t *testing.T) {
+	upgrade := func(sdk *twipla3as.TwiplaSDK) error {
+		return sdk.UpgradeWebsiteSubscription(t.Context(), twipla3as.UpgradeWebsiteSubscriptionArgs{WebsiteID: "website-id", PackageID: "package-id"})
+	}
+	downgrade := func(sdk *twipla3as.TwiplaSDK) error {
+		return sdk.DowngradeINTPCSubscription(t.Context(), twipla3as.DowngradeINTPCSubscriptionArgs{IntpcID: "intpc-id", PackageID: "package-id"})
+	}
+	resume := func(sdk *twipla3as.TwiplaSDK) error {
+		return sdk.ResumeINTPCSubscription(t.Context(), twipla3as.ResumeINTPCSubscriptionArgs{IntpcID: "intpc-id"})
+	}
+
+	for _, tc := range []struct {
+		call    func(*twipla3as.TwiplaSDK) error
+		status  int
+		message string
+		reason  error
+	}{
+		{call: upgrade, status: http.StatusBadRequest, message: "New package must have more touchpoints than the current one", reason: twipla3as.ErrNotAnUpgrade},
+		{call: upgrade, status: http.StatusBadRequest, message: "Package period does not match the subscription period", reason: twipla3as.ErrPeriodMismatch},
+		{call: upgrade, status: http.StatusConflict, message: "Subscription is already canceled", reason: twipla3as.ErrSubscriptionCanceled},
+		{call: downgrade, status: http.StatusBadRequest, message: "Cannot downgrade to a package with higher touchpoints", reason: twipla3as.ErrNotADowngrade},
+		{call: downgrade, status: http.StatusBadRequest, message: "Invalid subscription type for this INTP", reason: twipla3as.ErrWrongSubscriptionType},
+		{call: resume, status: http.StatusBadRequest, message: "Subscription was cancelled", reason: twipla3as.ErrSubscriptionCanceled},
+		{call: resume, status: http.StatusBadRequest, message: "Subscription must have more touchpoints"},
+		{call: upgrade, status: http.StatusNotFound, message: "Subscription is already canceled"},
+		{call: upgrade, status: http.StatusBadRequest, message: "Package not found"},
+		{call: upgrade, status: http.StatusBadRequest, message: "Subscription is already canceled.", reason: twipla3as.ErrSubscriptionCanceled},
+		{call: upgrade, status: http.StatusBadRequest, message: "Cannot downgrade to a package with higher touchpoints"},
+		{call: upgrade, status: http.StatusBadRequest, message: "Touchpoints must be greater than zero regardless of the package"},
+		{call: upgrade, status: http.StatusBadRequest, message: "Request rejected unless the package is active"},
+		{call: downgrade, status: http.StatusBadRequest, message: "Invalid request body, see the documentation for more information"},
+		{call: downgrade, status: http.StatusBadRequest, message: "Invalid type for field packageId"},
+		{call: downgrade, status: http.StatusBadRequest, message: "Package period is invalid, it must match one of monthly or yearly"},
+	} {
+		sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(r, tc.status, `{"message":"`+tc.message+`"}`), nil
+		})
+
+		err := tc.call(sdk)
+		var subErr *twipla3as.SubscriptionError
+		if tc.reason == nil {
+			assert.False(t, errors.As(err, &subErr), tc.message)
+			continue
+		}
+		if assert.ErrorAs(t, err, &subErr, tc.message) {
+			assert.Equal(t, tc.reason, subErr.Reason)
+		}
+		assert.ErrorIs(t, err, tc.reason)
+		var apiErr twipla3as.APIError
+		if assert.ErrorAs(t, err, &apiErr) {
+			assert.Equal(t, tc.message, apiErr.Message)
+			assert.Equal(t, tc.status, apiErr.Status)
+		}
+	}
+}
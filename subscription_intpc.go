@@ -26,9 +26,14 @@ type UpgradeINTPCSubscriptionArgs struct {
 }
 
 // UpgradeINTPCSubscription upgrades an INTPC subscription to a new package immediately.
+// Refusals for a known reason, such as [ErrNotAnUpgrade], are returned as a [SubscriptionError].
 func (sdk *TwiplaSDK) UpgradeINTPCSubscription(ctx context.Context, args UpgradeINTPCSubscriptionArgs) error {
-	_, err := parseResponse[intpcSubscription](sdk.apiCall(ctx, http.MethodPost, "/v3/3as/intpc-subscriptions/upgrade", args))
-	return err
+	if err := args.Validate(); err != nil {
+		return err
+	}
+	op := newOperation("UpgradeINTPCSubscription", http.MethodPost, "/v3/3as/intpc-subscriptions/upgrade").forIntpc(args.IntpcID)
+	_, err := parseResponse[intpcSubscription](sdk.apiCall(ctx, op, args))
+	return subscriptionError(op, err)
 }
 
 type DowngradeINTPCSubscriptionArgs struct {
@@ -41,9 +46,14 @@ type DowngradeINTPCSubscriptionArgs struct {
 }
 
 // DowngradeINTPCSubscription schedules an INTPC subscription downgrade to a lesser package at the beginning of the next billing period.
+// Refusals for a known reason, such as [ErrNotADowngrade], are returned as a [SubscriptionError].
 func (sdk *TwiplaSDK) DowngradeINTPCSubscription(ctx context.Context, args DowngradeINTPCSubscriptionArgs) error {
-	_, err := parseResponse[intpcSubscription](sdk.apiCall(ctx, http.MethodPost, "/v3/3as/intpc-subscriptions/downgrade", args))
-	return err
+	if err := args.Validate(); err != nil {
+		return err
+	}
+	op := newOperation("DowngradeINTPCSubscription", http.MethodPost, "/v3/3as/intpc-subscriptions/downgrade").forIntpc(args.IntpcID)
+	_, err := parseResponse[intpcSubscription](sdk.apiCall(ctx, op, args))
+	return subscriptionError(op, err)
 }
 
 type ResumeINTPCSubscriptionArgs struct {
@@ -53,8 +63,12 @@ type ResumeINTPCSubscriptionArgs struct {
 
 // ResumeINTPCSubscription resumes an INTPC subscription.
 func (sdk *TwiplaSDK) ResumeINTPCSubscription(ctx context.Context, args ResumeINTPCSubscriptionArgs) error {
-	_, err := parseResponse[intpcSubscription](sdk.apiCall(ctx, http.MethodPost, "/v3/3as/intpc-subscriptions/resume", args))
-	return err
+	if err := args.Validate(); err != nil {
+		return err
+	}
+	op := newOperation("ResumeINTPCSubscription", http.MethodPost, "/v3/3as/intpc-subscriptions/resume").forIntpc(args.IntpcID)
+	_, err := parseResponse[intpcSubscription](sdk.apiCall(ctx, op, args))
+	return subscriptionError(op, err)
 }
 
 type DeactivateINTPCSubscriptionArgs struct {
@@ -64,8 +78,12 @@ type DeactivateINTPCSubscriptionArgs struct {
 
 // DeactivateINTPCSubscription deactivates an INTPC subscription immediately.
 func (sdk *TwiplaSDK) DeactivateINTPCSubscription(ctx context.Context, args DeactivateINTPCSubscriptionArgs) error {
-	_, err := parseResponse[intpcSubscription](sdk.apiCall(ctx, http.MethodPost, "/v3/3as/intpc-subscriptions/deactivate", args))
-	return err
+	if err := args.Validate(); err != nil {
+		return err
+	}
+	op := newOperation("DeactivateINTPCSubscription", http.MethodPost, "/v3/3as/intpc-subscriptions/deactivate").forIntpc(args.IntpcID)
+	_, err := parseResponse[intpcSubscription](sdk.apiCall(ctx, op, args))
+	return subscriptionError(op, err)
 }
 
 type CancelINTPCSubscriptionArgs struct {
@@ -75,6 +93,10 @@ type CancelINTPCSubscriptionArgs struct {
 
 // CancelINTPCSubscription cancels an INTPC subscription after the end of the billing period.
 func (sdk *TwiplaSDK) CancelINTPCSubscription(ctx context.Context, args CancelINTPCSubscriptionArgs) error {
-	_, err := parseResponse[intpcSubscription](sdk.apiCall(ctx, http.MethodPost, "/v3/3as/intpc-subscriptions/cancel", args))
-	return err
+	if err := args.Validate(); err != nil {
+		return err
+	}
+	op := newOperation("CancelINTPCSubscription", http.MethodPost, "/v3/3as/intpc-subscriptions/cancel").forIntpc(args.IntpcID)
+	_, err := parseResponse[intpcSubscription](sdk.apiCall(ctx, op, args))
+	return subscriptionError(op, err)
 }
@@ -26,9 +26,14 @@ type UpgradeWebsiteSubscriptionArgs struct {
 }
 
 // UpgradeWebsiteSubscription upgrades a Website subscription to a new package immediately.
+// Refusals for a known reason, such as [ErrNotAnUpgrade], are returned as a [SubscriptionError].
 func (sdk *TwiplaSDK) UpgradeWebsiteSubscription(ctx context.Context, args UpgradeWebsiteSubscriptionArgs) error {
-	_, err := parseResponse[websiteSubscription](sdk.apiCall(ctx, http.MethodPost, "/v3/3as/website-subscriptions/upgrade", args))
-	return err
+	if err := args.Validate(); err != nil {
+		return err
+	}
+	op := newOperation("UpgradeWebsiteSubscription", http.MethodPost, "/v3/3as/website-subscriptions/upgrade").forWebsite(args.WebsiteID)
+	_, err := parseResponse[websiteSubscription](sdk.apiCall(ctx, op, args))
+	return subscriptionError(op, err)
 }
 
 type DowngradeWebsiteSubscriptionArgs struct {
@@ -41,9 +46,14 @@ type DowngradeWebsiteSubscriptionArgs struct {
 }
 
 // DowngradeWebsiteSubscription schedules a Website subscription downgrade to a lesser package at the beginning of the next billing period.
+// Refusals for a known reason, such as [ErrNotADowngrade], are returned as a [SubscriptionError].
 func (sdk *TwiplaSDK) DowngradeWebsiteSubscription(ctx context.Context, args DowngradeWebsiteSubscriptionArgs) error {
-	_, err := parseResponse[websiteSubscription](sdk.apiCall(ctx, http.MethodPost, "/v3/3as/website-subscriptions/downgrade", args))
-	return err
+	if err := args.Validate(); err != nil {
+		return err
+	}
+	op := newOperation("DowngradeWebsiteSubscription", http.MethodPost, "/v3/3as/website-subscriptions/downgrade").forWebsite(args.WebsiteID)
+	_, err := parseResponse[websiteSubscription](sdk.apiCall(ctx, op, args))
+	return subscriptionError(op, err)
 }
 
 type ResumeWebsiteSubscriptionArgs struct {
@@ -53,8 +63,12 @@ type ResumeWebsiteSubscriptionArgs struct {
 
 // ResumeWebsiteSubscription resumes a Website subscription.
 func (sdk *TwiplaSDK) ResumeWebsiteSubscription(ctx context.Context, args ResumeWebsiteSubscriptionArgs) error {
-	_, err := parseResponse[websiteSubscription](sdk.apiCall(ctx, http.MethodPost, "/v3/3as/website-subscriptions/resume", args))
-	return err
+	if err := args.Validate(); err != nil {
+		return err
+	}
+	op := newOperation("ResumeWebsiteSubscription", http.MethodPost, "/v3/3as/website-subscriptions/resume").forWebsite(args.WebsiteID)
+	_, err := parseResponse[websiteSubscription](sdk.apiCall(ctx, op, args))
+	return subscriptionError(op, err)
 }
 
 type DeactivateWebsiteSubscriptionArgs struct {
@@ -64,8 +78,12 @@ type DeactivateWebsiteSubscriptionArgs struct {
 
 // DeactivateWebsiteSubscription deactivates a Website subscription immediately.
 func (sdk *TwiplaSDK) DeactivateWebsiteSubscription(ctx context.Context, args DeactivateWebsiteSubscriptionArgs) error {
-	_, err := parseResponse[websiteSubscription](sdk.apiCall(ctx, http.MethodPost, "/v3/3as/website-subscriptions/deactivate", args))
-	return err
+	if err := args.Validate(); err != nil {
+		return err
+	}
+	op := newOperation("DeactivateWebsiteSubscription", http.MethodPost, "/v3/3as/website-subscriptions/deactivate").forWebsite(args.WebsiteID)
+	_, err := parseResponse[websiteSubscription](sdk.apiCall(ctx, op, args))
+	return subscriptionError(op, err)
 }
 
 type CancelWebsiteSubscriptionArgs struct {
@@ -75,6 +93,10 @@ type CancelWebsiteSubscriptionArgs struct {
 
 // CancelWebsiteSubscription cancels a Website subscription after the end of the billing period.
 func (sdk *TwiplaSDK) CancelWebsiteSubscription(ctx context.Context, args CancelWebsiteSubscriptionArgs) error {
-	_, err := parseResponse[websiteSubscription](sdk.apiCall(ctx, http.MethodPost, "/v3/3as/website-subscriptions/cancel", args))
-	return err
+	if err := args.Validate(); err != nil {
+		return err
+	}
+	op := newOperation("CancelWebsiteSubscription", http.MethodPost, "/v3/3as/website-subscriptions/cancel").forWebsite(args.WebsiteID)
+	_, err := parseResponse[websiteSubscription](sdk.apiCall(ctx, op, args))
+	return subscriptionError(op, err)
 }
@@ -1,47 +1,246 @@
 package twipla3as
 
 import (
-	"crypto/rsa"
-	"github.com/golang-jwt/jwt/v5"
+	"cmp"
+	"container/list"
+	"context"
+	"sync"
 	"time"
 )
 
+const (
+	// tokenLifetime is the validity of the signed INTP and INTPC tokens.
+	tokenLifetime = 4 * time.Hour
+	// intpTokenRefreshMargin is how long before its expiry a cached INTP token is replaced.
+	intpTokenRefreshMargin = 5 * time.Minute
+	// intpcTokenRefreshMargin is how long before its expiry a cached INTPC token is replaced.
+	// It is larger than intpTokenRefreshMargin since INTPC tokens are handed over to the dashboard, which keeps using them.
+	intpcTokenRefreshMargin = time.Hour
+
+	// DefaultIntpcTokenCacheSize is the number of INTPC tokens cached when [TwiplaConfig.IntpcTokenCacheSize] is zero.
+	DefaultIntpcTokenCacheSize = 1024
+)
+
 func (sdk *TwiplaSDK) IntpAccessToken() (string, error) {
 	return sdk.signer.IntpToken()
 }
 
 func (sdk *TwiplaSDK) IntpcAccessToken(intpcID string) (string, error) {
-	return sdk.signer.IntpcToken(intpcID)
+	return sdk.signer.IntpcToken(context.Background(), intpcID)
+}
+
+// ResetTokenCache drops all the cached INTP and INTPC tokens, so that fresh ones are signed on their next use.
+// They are already dropped when a call fails with [ErrInvalidAccessToken].
+func (sdk *TwiplaSDK) ResetTokenCache() {
+	sdk.signer.reset()
 }
 
 type tokenSigner struct {
-	privateKey *rsa.PrivateKey
-	intpID     string
+	keys   *KeyRing
+	intpID string
+
+	mu    sync.Mutex
+	intp  cachedToken
+	intpc *tokenCache
+	// intpLevel is the level in keys of the key signing INTP and INTPC tokens,
+	// which is not the primary key after falling back to a previous one.
+	intpLevel int
+	// retryPrimaryAt is when to try the primary key again, after falling back to a previous one.
+	retryPrimaryAt time.Time
+	// generation is the generation of keys the cached tokens were signed with.
+	generation uint64
+}
+
+type cachedToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+func (c cachedToken) valid(margin time.Duration) bool {
+	return c.token != "" && time.Until(c.expiresAt) > margin
+}
+
+func newTokenSigner(keys *KeyRing, intpID string, intpcCacheSize int) *tokenSigner {
+	if intpcCacheSize == 0 {
+		intpcCacheSize = DefaultIntpcTokenCacheSize
+	}
+	return &tokenSigner{
+		keys:   keys,
+		intpID: intpID,
+		intpc:  newTokenCache(intpcCacheSize),
+	}
 }
 
 func (t *tokenSigner) IntpToken() (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+	token, _, err := t.intpToken(context.Background(), true)
+	return token, err
+}
+
+// intpToken returns the INTP token, reusing the cached one if allowed and still valid,
+// along with the level in t.keys of the key that signed it.
+func (t *tokenSigner) intpToken(ctx context.Context, useCache bool) (string, int, error) {
+	t.mu.Lock()
+	t.sync()
+	cached, level := t.intp, t.intpLevel
+	t.mu.Unlock()
+	if useCache && cached.valid(intpTokenRefreshMargin) {
+		return cached.token, level, nil
+	}
+
+	now := time.Now()
+	signed, err := t.keys.signWith(ctx, map[string]any{
 		"iss":     "twipla-3as-go-sdk",
 		"roles":   []string{"intp"},
 		"intp_id": t.intpID,
-		"iat":     time.Now().Unix(),
-		"exp":     time.Now().Add(time.Hour * 4).Unix(),
-	})
+		"iat":     now.Unix(),
+		"exp":     now.Add(tokenLifetime).Unix(),
+	}, level)
+	if err != nil {
+		return "", level, err
+	}
 
-	token.Header["kid"] = t.intpID
-	return token.SignedString(t.privateKey)
+	t.mu.Lock()
+	if t.intpLevel == level {
+		t.intp = cachedToken{token: signed, expiresAt: now.Add(tokenLifetime)}
+	}
+	t.mu.Unlock()
+	return signed, level, nil
 }
 
-func (t *tokenSigner) IntpcToken(intpcID string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+// IntpcToken returns the INTPC token of intpcID, signed with the same key as the INTP tokens,
+// so that dashboards keep working while the SDK falls back to a previous key during a rotation.
+func (t *tokenSigner) IntpcToken(ctx context.Context, intpcID string) (string, error) {
+	t.mu.Lock()
+	t.sync()
+	cached, ok := t.intpc.get(intpcID)
+	level := t.intpLevel
+	t.mu.Unlock()
+	if ok && cached.valid(intpcTokenRefreshMargin) {
+		return cached.token, nil
+	}
+
+	now := time.Now()
+	signed, err := t.keys.signWith(ctx, map[string]any{
 		"iss":      "twipla-3as-go-sdk",
 		"roles":    []string{"intpc"},
 		"intp_id":  t.intpID,
 		"intpc_id": intpcID,
-		"iat":      time.Now().Unix(),
-		"exp":      time.Now().Add(time.Hour * 4).Unix(),
-	})
+		"iat":      now.Unix(),
+		"exp":      now.Add(tokenLifetime).Unix(),
+	}, level)
+	if err != nil {
+		return "", err
+	}
+
+	t.mu.Lock()
+	if t.intpLevel == level {
+		t.intpc.put(intpcID, cachedToken{token: signed, expiresAt: now.Add(tokenLifetime)})
+	}
+	t.mu.Unlock()
+	return signed, nil
+}
+
+// fallback drops the cached tokens after an INTP token signed with the key at level rejected was refused as invalid,
+// and moves on to the next key of the ring, if any, such as the previous key during a rotation.
+// The primary key is tried again after the ring's PrimaryRetryInterval.
+// It returns the level of the key signing the next INTP token, and whether it differs from the rejected one.
+func (t *tokenSigner) fallback(rejected int) (int, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.intpLevel == rejected {
+		t.intp = cachedToken{}
+		t.intpc.clear()
+		levels, _ := t.keys.state()
+		t.intpLevel = rejected + 1
+		if t.intpLevel >= levels {
+			t.intpLevel = 0
+		}
+		t.retryPrimaryAt = time.Now().Add(cmp.Or(t.keys.PrimaryRetryInterval, DefaultPrimaryRetryInterval))
+	}
+	return t.intpLevel, t.intpLevel != rejected
+}
+
+// sync drops the cached tokens once the primary key was rotated, once the previous key signing INTP tokens retired,
+// or once it is time to try the primary key again. t.mu must be held.
+func (t *tokenSigner) sync() {
+	levels, generation := t.keys.state()
+	switch {
+	case generation != t.generation:
+		t.generation = generation
+		t.intpLevel = 0
+		t.intp = cachedToken{}
+		t.intpc.clear()
+	case t.intpLevel >= levels, t.intpLevel != 0 && !time.Now().Before(t.retryPrimaryAt):
+		t.intpLevel = 0
+		t.intp = cachedToken{}
+		t.intpc.clear()
+	}
+}
+
+func (t *tokenSigner) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.intp = cachedToken{}
+	t.intpc.clear()
+}
+
+// tokenCache is a fixed size LRU cache of INTPC tokens, keyed by INTPC ID.
+// It is not safe for concurrent use. A nil or zero sized cache holds no tokens.
+type tokenCache struct {
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type tokenCacheEntry struct {
+	key   string
+	token cachedToken
+}
+
+func newTokenCache(size int) *tokenCache {
+	if size <= 0 {
+		return nil
+	}
+	return &tokenCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+func (c *tokenCache) get(key string) (cachedToken, bool) {
+	if c == nil {
+		return cachedToken{}, false
+	}
+	elem, ok := c.entries[key]
+	if !ok {
+		return cachedToken{}, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*tokenCacheEntry).token, true
+}
+
+func (c *tokenCache) put(key string, token cachedToken) {
+	if c == nil {
+		return
+	}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*tokenCacheEntry).token = token
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&tokenCacheEntry{key: key, token: token})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*tokenCacheEntry).key)
+	}
+}
 
-	token.Header["kid"] = t.intpID
-	return token.SignedString(t.privateKey)
+func (c *tokenCache) clear() {
+	if c == nil {
+		return
+	}
+	c.order.Init()
+	clear(c.entries)
 }
@@ -0,0 +1,66 @@
+package twipla3as_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+	"github.com/twipla/3as-go-sdk/twipla3astest"
+)
+
+func TestTokenCache(t *testing.T) {
+	signer, err := twipla3astest.NewSigner("test-intp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// signed returns the roles of the tokens signed so far, so that cached tokens can be told apart from fresh ones.
+	signed := func() []string {
+		var roles []string
+		for _, claims := range signer.Claims() {
+			roles = append(roles, claims["roles"].([]string)[0])
+		}
+		return roles
+	}
+
+	var tokens []string
+	sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+		tokens = append(tokens, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		if len(tokens) == 2 {
+			return jsonResponse(r, http.StatusUnauthorized, `{"status":401,"error":"invalid access token"}`), nil
+		}
+		return jsonResponse(r, http.StatusOK, `{"payload":[]}`), nil
+	}, func(c *twipla3as.TwiplaConfig) {
+		c.PrivateKey = ""
+		c.Signer = signer
+	})
+
+	intpcToken, err := sdk.IntpcAccessToken("intpc-id")
+	assert.NoError(t, err)
+	_, err = sdk.Packages(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"intpc", "intp"}, signed())
+
+	cachedIntpcToken, err := sdk.IntpcAccessToken("intpc-id")
+	assert.NoError(t, err)
+	assert.Equal(t, intpcToken, cachedIntpcToken)
+	_, err = sdk.IntpcAccessToken("other-intpc-id")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"intpc", "intp", "intpc"}, signed())
+
+	_, err = sdk.Packages(t.Context())
+	assert.ErrorIs(t, err, twipla3as.ErrInvalidAccessToken)
+	_, err = sdk.Packages(t.Context())
+	assert.NoError(t, err)
+	if assert.Len(t, tokens, 3) {
+		assert.Equal(t, tokens[0], tokens[1])
+	}
+	// The refused INTP token was dropped, so a fresh one was signed for the last call.
+	assert.Equal(t, []string{"intpc", "intp", "intpc", "intp"}, signed())
+
+	sdk.ResetTokenCache()
+	_, err = sdk.IntpcAccessToken("intpc-id")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"intpc", "intp", "intpc", "intp", "intpc"}, signed())
+}
@@ -0,0 +1,87 @@
+package twipla3as
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+// Tracer starts a span for every operation of the SDK, such as [TwiplaSDK.CreateINTPC].
+// It is meant to be implemented on top of a tracing library such as OpenTelemetry,
+// where Start wraps trace.Tracer.Start and Inject wraps the global propagator's Inject.
+type Tracer interface {
+	// Start starts a span named name, as a child of the span in ctx, if any.
+	Start(ctx context.Context, name string) (context.Context, Span)
+	// Inject propagates the trace context of ctx to the headers of an outgoing request.
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span is a single operation of the SDK, as started by a [Tracer].
+type Span interface {
+	SetAttributes(attrs ...slog.Attr)
+	// RecordError marks the span as failed because of err.
+	RecordError(err error)
+	End()
+}
+
+// Attribute keys set on the SDK's spans.
+const (
+	// AttributeOperation is the name of the SDK method, such as "CreateINTPC".
+	AttributeOperation = "twipla3as.operation"
+	// AttributeIntpcID is the INTP's ID of the customer concerned by the operation.
+	AttributeIntpcID = "twipla3as.intpc_id"
+	// AttributeWebsiteID is the INTP's ID of the website concerned by the operation.
+	AttributeWebsiteID = "twipla3as.website_id"
+	// AttributeErrorCode is the [APIError.Code] returned by the API.
+	AttributeErrorCode = "twipla3as.error_code"
+	// AttributeHTTPMethod is the HTTP method of the request.
+	AttributeHTTPMethod = "http.request.method"
+	// AttributeHTTPRoute is the route template of the request, such as "/v2/3as/websites/{websiteId}".
+	// The raw path is not used, since it contains IDs.
+	AttributeHTTPRoute = "http.route"
+	// AttributeHTTPStatusCode is the status code of the response.
+	AttributeHTTPStatusCode = "http.response.status_code"
+)
+
+// startSpan starts the span of an operation, if a tracer is configured.
+func (sdk *TwiplaSDK) startSpan(ctx context.Context, op operation) (context.Context, Span) {
+	if sdk.tracer == nil {
+		return ctx, nil
+	}
+	ctx, span := sdk.tracer.Start(ctx, "twipla3as."+op.name)
+	attrs := []slog.Attr{
+		slog.String(AttributeOperation, op.name),
+		slog.String(AttributeHTTPMethod, op.method),
+		slog.String(AttributeHTTPRoute, op.route),
+	}
+	if op.intpcID != "" {
+		attrs = append(attrs, slog.String(AttributeIntpcID, op.intpcID))
+	}
+	if op.websiteID != "" {
+		attrs = append(attrs, slog.String(AttributeWebsiteID, op.websiteID))
+	}
+	span.SetAttributes(attrs...)
+	return ctx, span
+}
+
+// endSpan records the outcome of an operation on its span, and ends it.
+func endSpan(span Span, resp *http.Response, err error) {
+	if span == nil {
+		return
+	}
+	defer span.End()
+	if err == nil {
+		span.SetAttributes(slog.Int(AttributeHTTPStatusCode, resp.StatusCode))
+		return
+	}
+	var apiErr APIError
+	if errors.As(err, &apiErr) {
+		span.SetAttributes(slog.Int(AttributeHTTPStatusCode, apiErr.Status), slog.Int(AttributeErrorCode, apiErr.Code))
+	}
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		span.SetAttributes(slog.Int(AttributeHTTPStatusCode, httpErr.StatusCode))
+	}
+	span.RecordError(err)
+}
@@ -0,0 +1,96 @@
+package twipla3as_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+type testSpan struct {
+	name  string
+	attrs map[string]any
+	err   error
+	ended bool
+}
+
+func (s *testSpan) SetAttributes(attrs ...slog.Attr) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value.Any()
+	}
+}
+
+func (s *testSpan) RecordError(err error) {
+	s.err = err
+}
+
+func (s *testSpan) End() {
+	s.ended = true
+}
+
+type spanKey struct{}
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, twipla3as.Span) {
+	span := &testSpan{name: name, attrs: map[string]any{}}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (t *testTracer) Inject(ctx context.Context, header http.Header) {
+	if span, ok := ctx.Value(spanKey{}).(*testSpan); ok {
+		header.Set("X-Test-Span", span.name)
+	}
+}
+
+func TestTracing(t *testing.T) {
+	tracer := &testTracer{}
+	sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "twipla3as."+r.Header.Get("X-Test-Operation"), r.Header.Get("X-Test-Span"))
+		if r.Method == http.MethodDelete {
+			return jsonResponse(r, http.StatusNotFound, `{"status":404,"message":"not found","code":404001}`), nil
+		}
+		return jsonResponse(r, http.StatusOK, `{"payload":{"id":"website-uuid"}}`), nil
+	}, func(c *twipla3as.TwiplaConfig) {
+		c.Tracer = tracer
+		c.Middleware = []twipla3as.Middleware{func(next twipla3as.Handler) twipla3as.Handler {
+			return func(r *http.Request) (*http.Response, error) {
+				r.Header.Set("X-Test-Operation", tracer.spans[len(tracer.spans)-1].attrs[twipla3as.AttributeOperation].(string))
+				return next(r)
+			}
+		}}
+	})
+
+	_, err := sdk.Website(t.Context(), "website-id")
+	assert.NoError(t, err)
+	err = sdk.DeleteWebsiteApiKey(t.Context(), "website-id", "key-id")
+	assert.Error(t, err)
+
+	if assert.Len(t, tracer.spans, 2) {
+		get := tracer.spans[0]
+		assert.Equal(t, "twipla3as.Website", get.name)
+		assert.True(t, get.ended)
+		assert.NoError(t, get.err)
+		assert.Equal(t, map[string]any{
+			twipla3as.AttributeOperation:      "Website",
+			twipla3as.AttributeHTTPMethod:     http.MethodGet,
+			twipla3as.AttributeHTTPRoute:      "/v2/3as/websites/{websiteId}",
+			twipla3as.AttributeWebsiteID:      "website-id",
+			twipla3as.AttributeHTTPStatusCode: int64(http.StatusOK),
+		}, get.attrs)
+
+		del := tracer.spans[1]
+		assert.Equal(t, "twipla3as.DeleteWebsiteApiKey", del.name)
+		assert.True(t, del.ended)
+		assert.Error(t, del.err)
+		assert.Equal(t, "/v2/3as/websites/{websiteId}/api-keys/{apiKeyId}", del.attrs[twipla3as.AttributeHTTPRoute])
+		assert.Equal(t, int64(http.StatusNotFound), del.attrs[twipla3as.AttributeHTTPStatusCode])
+		assert.Equal(t, int64(404001), del.attrs[twipla3as.AttributeErrorCode])
+	}
+}
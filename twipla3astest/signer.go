@@ -0,0 +1,79 @@
+// Package twipla3astest provides helpers to exercise the TWIPLA 3AS SDK offline, in tests.
+package twipla3astest
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"maps"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+// Signer is a [twipla3as.Signer] backed by an RSA key generated locally.
+// It records the claims of every token it signs, and can verify them.
+type Signer struct {
+	key    *rsa.PrivateKey
+	signer twipla3as.Signer
+
+	mu     sync.Mutex
+	claims []map[string]any
+}
+
+// NewSigner generates a key and returns a [Signer] setting the "kid" header of tokens to intpID.
+func NewSigner(intpID string) (*Signer, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := twipla3as.NewCryptoSigner(key, intpID, "")
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{key: key, signer: signer}, nil
+}
+
+// SignJWT signs a token holding claims.
+func (s *Signer) SignJWT(ctx context.Context, claims map[string]any) (string, error) {
+	s.mu.Lock()
+	s.claims = append(s.claims, maps.Clone(claims))
+	s.mu.Unlock()
+	return s.signer.SignJWT(ctx, claims)
+}
+
+// Claims returns the claims of the tokens signed so far, in order.
+func (s *Signer) Claims() []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]map[string]any(nil), s.claims...)
+}
+
+// PublicKey returns the public key verifying the signed tokens.
+func (s *Signer) PublicKey() *rsa.PublicKey {
+	return &s.key.PublicKey
+}
+
+// PublicKeyPEM returns the public key in the PEM format registered with TWIPLA.
+func (s *Signer) PublicKeyPEM() string {
+	der, err := x509.MarshalPKIXPublicKey(&s.key.PublicKey)
+	if err != nil {
+		panic(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// Verify checks the signature and expiry of a token signed by s, such as the bearer token of a request, and returns its claims.
+func (s *Signer) Verify(token string) (map[string]any, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return &s.key.PublicKey, nil
+	}, jwt.WithValidMethods([]string{"RS256"}))
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
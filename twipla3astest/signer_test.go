@@ -0,0 +1,53 @@
+package twipla3astest_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+	"github.com/twipla/3as-go-sdk/twipla3astest"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestSigner(t *testing.T) {
+	signer, err := twipla3astest.NewSigner("test-intp")
+	assert.NoError(t, err)
+
+	sdk, err := twipla3as.NewSDK(&twipla3as.TwiplaConfig{
+		IntpID: "test-intp",
+		Signer: signer,
+		HTTPClient: &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			claims, err := signer.Verify(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+			assert.NoError(t, err)
+			assert.Equal(t, "test-intp", claims["intp_id"])
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(`{"payload":[]}`)),
+				Request:    r,
+			}, nil
+		})},
+	})
+	assert.NoError(t, err)
+
+	_, err = sdk.Packages(t.Context())
+	assert.NoError(t, err)
+	iframeURL, err := sdk.GenerateIframeURL("intpc-id", "website-id")
+	assert.NoError(t, err)
+	assert.Contains(t, iframeURL, "intpc_token=")
+
+	claims := signer.Claims()
+	if assert.Len(t, claims, 2) {
+		assert.Equal(t, []string{"intp"}, claims[0]["roles"])
+		assert.Equal(t, "intpc-id", claims[1]["intpc_id"])
+	}
+	assert.Contains(t, signer.PublicKeyPEM(), "BEGIN PUBLIC KEY")
+}
@@ -0,0 +1,220 @@
+package twipla3as
+
+import (
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ValidationError is returned, before any request is made, when the arguments of a call are invalid.
+// It lists every problem found, and matches [ErrValidation] with [errors.Is].
+type ValidationError struct {
+	Fields []FieldError
+
+	// causes are matched by [errors.Is] besides [ErrValidation], such as [ErrInvalidSubscriptionType].
+	causes []error
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		problems[i] = field.Error()
+	}
+	return "invalid arguments: " + strings.Join(problems, "; ")
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation || slices.Contains(e.causes, target)
+}
+
+// validator collects the problems found in the arguments of a call.
+type validator struct {
+	err ValidationError
+}
+
+func (v *validator) check(ok bool, field string, reason string) {
+	if !ok {
+		v.err.Fields = append(v.err.Fields, FieldError{Field: field, Reason: reason})
+	}
+}
+
+func (v *validator) required(field string, value string) {
+	v.check(strings.TrimSpace(value) != "", field, "is required")
+}
+
+func (v *validator) email(field string, value string) {
+	if value == "" {
+		v.required(field, value)
+		return
+	}
+	addr, err := mail.ParseAddress(value)
+	v.check(err == nil && addr.Address == value, field, "must be a valid email address")
+}
+
+func (v *validator) domain(field string, value string) {
+	if value == "" {
+		v.required(field, value)
+		return
+	}
+	v.check(isDomain(value), field, "must be a domain name, such as twipla.com, without scheme, port or path")
+}
+
+func (v *validator) oneOf(field string, value string, allowed ...string) {
+	v.check(slices.Contains(allowed, value), field, "must be one of "+strings.Join(allowed, ", "))
+}
+
+// cause makes the returned error also match err with [errors.Is].
+func (v *validator) cause(err error) {
+	v.err.causes = append(v.err.causes, err)
+}
+
+func (v *validator) result() error {
+	if len(v.err.Fields) == 0 {
+		return nil
+	}
+	return &v.err
+}
+
+// isDomain reports whether s is a valid domain name with at least two labels, such as "twipla.com".
+func isDomain(s string) bool {
+	if len(s) > 253 || !strings.Contains(s, ".") {
+		return false
+	}
+	for label := range strings.SplitSeq(s, ".") {
+		if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+		for _, r := range label {
+			if r != '-' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Validate reports every problem of the arguments. It is run by [TwiplaSDK.CreateINTPC] before any request is made.
+func (args CreateINTPCArgs) Validate() error {
+	var v validator
+	v.required("ExternalCustomerID", args.ExternalCustomerID)
+	v.email("Email", args.Email)
+	if args.SubscriptionType != SubscriptionTypeWebsite && args.SubscriptionType != SubscriptionTypeINTPC {
+		v.oneOf("SubscriptionType", string(args.SubscriptionType), string(SubscriptionTypeWebsite), string(SubscriptionTypeINTPC))
+		v.cause(ErrInvalidSubscriptionType)
+	}
+	v.required("ExternalWebsiteID", args.ExternalWebsiteID)
+	v.domain("Domain", args.Domain)
+	return v.result()
+}
+
+// Validate reports every problem of the arguments. It is run by [TwiplaSDK.CreateWebsite] before any request is made.
+func (args CreateWebsiteArgs) Validate() error {
+	var v validator
+	v.required("ExternalID", args.ExternalID)
+	v.required("IntpcID", args.IntpcID)
+	v.domain("Domain", args.Domain)
+	return v.result()
+}
+
+// Validate reports every problem of the arguments. It is run by [TwiplaSDK.CreatePackage] before any request is made.
+func (args CreatePackageArgs) Validate() error {
+	var v validator
+	v.required("Name", args.Name)
+	v.check(args.Touchpoints >= 0, "Touchpoints", "must not be negative")
+	v.check(args.Price >= 0, "Price", "must not be negative")
+	v.oneOf("Currency", string(args.Currency), string(CurrencyEUR), string(CurrencyRON), string(CurrencyUSD))
+	v.oneOf("Period", string(args.Period), string(PeriodMonthly), string(PeriodYearly))
+	return v.result()
+}
+
+// Validate reports every problem of the arguments. It is run by [TwiplaSDK.UpdatePackage] before any request is made.
+func (args UpdatePackageArgs) Validate() error {
+	var v validator
+	v.required("Name", args.Name)
+	return v.result()
+}
+
+// Validate reports every problem of the arguments. It is run by [TwiplaSDK.CreateWebsiteApiKey] before any request is made.
+func (args CreateApiKeyArgs) Validate() error {
+	var v validator
+	v.required("ExternalWebsiteID", args.ExternalWebsiteID)
+	v.required("Name", args.Name)
+	v.check(args.ExpiresAt == nil || args.ExpiresAt.After(time.Now()), "ExpiresAt", "must be in the future")
+	return v.result()
+}
+
+// Validate reports every problem of the arguments. It is run by [TwiplaSDK.UpgradeINTPCSubscription] before any request is made.
+func (args UpgradeINTPCSubscriptionArgs) Validate() error {
+	var v validator
+	v.required("IntpcID", args.IntpcID)
+	v.required("PackageID", args.PackageID)
+	return v.result()
+}
+
+// Validate reports every problem of the arguments. It is run by [TwiplaSDK.DowngradeINTPCSubscription] before any request is made.
+func (args DowngradeINTPCSubscriptionArgs) Validate() error {
+	var v validator
+	v.required("IntpcID", args.IntpcID)
+	v.required("PackageID", args.PackageID)
+	return v.result()
+}
+
+// Validate reports every problem of the arguments. It is run by [TwiplaSDK.ResumeINTPCSubscription] before any request is made.
+func (args ResumeINTPCSubscriptionArgs) Validate() error {
+	var v validator
+	v.required("IntpcID", args.IntpcID)
+	return v.result()
+}
+
+// Validate reports every problem of the arguments. It is run by [TwiplaSDK.DeactivateINTPCSubscription] before any request is made.
+func (args DeactivateINTPCSubscriptionArgs) Validate() error {
+	var v validator
+	v.required("IntpcID", args.IntpcID)
+	return v.result()
+}
+
+// Validate reports every problem of the arguments. It is run by [TwiplaSDK.CancelINTPCSubscription] before any request is made.
+func (args CancelINTPCSubscriptionArgs) Validate() error {
+	var v validator
+	v.required("IntpcID", args.IntpcID)
+	return v.result()
+}
+
+// Validate reports every problem of the arguments. It is run by [TwiplaSDK.UpgradeWebsiteSubscription] before any request is made.
+func (args UpgradeWebsiteSubscriptionArgs) Validate() error {
+	var v validator
+	v.required("WebsiteID", args.WebsiteID)
+	v.required("PackageID", args.PackageID)
+	return v.result()
+}
+
+// Validate reports every problem of the arguments. It is run by [TwiplaSDK.DowngradeWebsiteSubscription] before any request is made.
+func (args DowngradeWebsiteSubscriptionArgs) Validate() error {
+	var v validator
+	v.required("WebsiteID", args.WebsiteID)
+	v.required("PackageID", args.PackageID)
+	return v.result()
+}
+
+// Validate reports every problem of the arguments. It is run by [TwiplaSDK.ResumeWebsiteSubscription] before any request is made.
+func (args ResumeWebsiteSubscriptionArgs) Validate() error {
+	var v validator
+	v.required("WebsiteID", args.WebsiteID)
+	return v.result()
+}
+
+// Validate reports every problem of the arguments. It is run by [TwiplaSDK.DeactivateWebsiteSubscription] before any request is made.
+func (args DeactivateWebsiteSubscriptionArgs) Validate() error {
+	var v validator
+	v.required("WebsiteID", args.WebsiteID)
+	return v.result()
+}
+
+// Validate reports every problem of the arguments. It is run by [TwiplaSDK.CancelWebsiteSubscription] before any request is made.
+func (args CancelWebsiteSubscriptionArgs) Validate() error {
+	var v validator
+	v.required("WebsiteID", args.WebsiteID)
+	return v.result()
+}
@@ -0,0 +1,100 @@
+package twipla3as_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	twipla3as "github.com/twipla/3as-go-sdk"
+)
+
+func TestValidation(t *testing.T) {
+	sdk := newTestSDK(t, func(r *http.Request) (*http.Response, error) {
+		t.Fatal("invalid arguments should not reach the API")
+		return nil, nil
+	})
+
+	t.Run("every problem is listed", func(t *testing.T) {
+		_, err := sdk.CreatePackage(t.Context(), twipla3as.CreatePackageArgs{
+			Name:        "",
+			Touchpoints: -1,
+			Price:       10,
+			Currency:    "GBP",
+			Period:      twipla3as.PeriodYearly,
+		})
+		var validationErr *twipla3as.ValidationError
+		if assert.ErrorAs(t, err, &validationErr) {
+			assert.Equal(t, []twipla3as.FieldError{
+				{Field: "Name", Reason: "is required"},
+				{Field: "Touchpoints", Reason: "must not be negative"},
+				{Field: "Currency", Reason: "must be one of EUR, RON, USD"},
+			}, validationErr.Fields)
+		}
+		assert.ErrorIs(t, err, twipla3as.ErrValidation)
+		assert.EqualError(t, err, "invalid arguments: Name is required; Touchpoints must not be negative; Currency must be one of EUR, RON, USD")
+	})
+
+	t.Run("INTPC", func(t *testing.T) {
+		_, err := sdk.CreateINTPC(t.Context(), twipla3as.CreateINTPCArgs{
+			ExternalCustomerID: "intpc-id",
+			Email:              "Customer <customer@twipla.com>",
+			SubscriptionType:   "pool",
+			PackageID:          "package-id",
+			ExternalWebsiteID:  "website-id",
+			Domain:             "https://twipla.com/",
+		})
+		var validationErr *twipla3as.ValidationError
+		if assert.ErrorAs(t, err, &validationErr) {
+			fields := make([]string, len(validationErr.Fields))
+			for i, field := range validationErr.Fields {
+				fields[i] = field.Field
+			}
+			assert.Equal(t, []string{"Email", "SubscriptionType", "Domain"}, fields)
+		}
+		assert.ErrorIs(t, err, twipla3as.ErrInvalidSubscriptionType)
+
+		// PackageID is optional, as for the API.
+		assert.NoError(t, twipla3as.CreateINTPCArgs{
+			ExternalCustomerID: "intpc-id",
+			Email:              "customer@twipla.com",
+			SubscriptionType:   twipla3as.SubscriptionTypeWebsite,
+			ExternalWebsiteID:  "website-id",
+			Domain:             "twipla.com",
+		}.Validate())
+	})
+
+	t.Run("domains", func(t *testing.T) {
+		for domain, valid := range map[string]bool{
+			"twipla.com":         true,
+			"mail.google.com":    true,
+			"123.twiplatest.com": true,
+			"bücher.example":     true,
+			"twipla":             false,
+			"twipla.com:8080":    false,
+			"twipla.com/path":    false,
+			"-twipla.com":        false,
+			"twipla..com":        false,
+			"http://twipla.com":  false,
+			"twipla .com":        false,
+			"www.twipla.com.":    false,
+			"":                   false,
+		} {
+			err := twipla3as.CreateWebsiteArgs{ExternalID: "website-id", IntpcID: "intpc-id", Domain: domain}.Validate()
+			assert.Equal(t, valid, err == nil, "%q: %v", domain, err)
+		}
+	})
+
+	t.Run("subscriptions", func(t *testing.T) {
+		err := sdk.UpgradeWebsiteSubscription(t.Context(), twipla3as.UpgradeWebsiteSubscriptionArgs{})
+		assert.EqualError(t, err, "invalid arguments: WebsiteID is required; PackageID is required")
+		err = sdk.CancelINTPCSubscription(t.Context(), twipla3as.CancelINTPCSubscriptionArgs{IntpcID: " "})
+		assert.ErrorIs(t, err, twipla3as.ErrValidation)
+	})
+
+	t.Run("API keys", func(t *testing.T) {
+		expired := time.Now().Add(-time.Hour)
+		_, err := sdk.CreateWebsiteApiKey(t.Context(), twipla3as.CreateApiKeyArgs{ExternalWebsiteID: "website-id", Name: "ci", ExpiresAt: &expired})
+		assert.EqualError(t, err, "invalid arguments: ExpiresAt must be in the future")
+	})
+}
@@ -5,8 +5,8 @@ import (
 	"context"
 	"net/http"
 	"net/url"
-	"path"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -75,7 +75,12 @@ type CreateWebsiteArgs struct {
 	UFT bool
 }
 
+// CreateWebsite adds a website to an existing customer.
+// When a replayed call (see [WithIdempotencyKey]) finds the website already created, it succeeds.
 func (sdk *TwiplaSDK) CreateWebsite(ctx context.Context, args CreateWebsiteArgs) error {
+	if err := args.Validate(); err != nil {
+		return err
+	}
 	if args.BillingDate.IsZero() {
 		args.BillingDate = time.Now()
 	}
@@ -86,7 +91,15 @@ func (sdk *TwiplaSDK) CreateWebsite(ctx context.Context, args CreateWebsiteArgs)
 	apiArgs.Website.Package.BillingDate = args.BillingDate.UTC().Format(time.RFC3339)
 	apiArgs.Intpc.ID = args.IntpcID
 	apiArgs.Opts.UFT = args.UFT
-	_, err := parseResponse[any](sdk.apiCall(ctx, http.MethodPost, "/v3/3as/websites", apiArgs))
+	op := newOperation("CreateWebsite", http.MethodPost, "/v3/3as/websites").forIntpc(args.IntpcID).forWebsite(args.ExternalID)
+	_, err := parseResponse[any](sdk.apiCall(ctx, op, apiArgs))
+	if isReplayConflict(ctx, err) {
+		// The website was created by an earlier attempt, so the call succeeded if it matches the arguments.
+		existing, getErr := sdk.Website(ctx, args.ExternalID)
+		if getErr == nil && existing.IntpCustomerID == args.IntpcID && strings.EqualFold(existing.Domain, args.Domain) {
+			return nil
+		}
+	}
 	return err
 }
 
@@ -100,7 +113,7 @@ func (sdk *TwiplaSDK) IntpcWebsites(ctx context.Context, intpcID string, paginat
 
 // Website gets a website based on the INTP's own website ID.
 func (sdk *TwiplaSDK) Website(ctx context.Context, websiteID string) (Website, error) {
-	resp, err := parseResponse[Website](sdk.apiCall(ctx, http.MethodGet, path.Join("/v2/3as/websites", websiteID), nil))
+	resp, err := parseResponse[Website](sdk.apiCall(ctx, newOperation("Website", http.MethodGet, "/v2/3as/websites/{websiteId}", websiteID), nil))
 	if err != nil {
 		return Website{}, err
 	}
@@ -109,16 +122,19 @@ func (sdk *TwiplaSDK) Website(ctx context.Context, websiteID string) (Website, e
 
 // DeleteWebsite gets a website based on the INTP's own website ID.
 func (sdk *TwiplaSDK) DeleteWebsite(ctx context.Context, websiteID string) error {
-	_, err := parseResponse[any](sdk.apiCall(ctx, http.MethodDelete, path.Join("/v2/3as/websites", websiteID), nil))
+	_, err := parseResponse[any](sdk.apiCall(ctx, newOperation("DeleteWebsite", http.MethodDelete, "/v2/3as/websites/{websiteId}", websiteID), nil))
 	return err
 }
 
 func (sdk *TwiplaSDK) websites(ctx context.Context, intpcID string, pagination Pagination) ([]Website, PaginationMetadata, error) {
 	query := pagination.buildQuery()
+	name := "Websites"
 	if len(intpcID) > 0 {
 		query.Set("externalCustomerId", intpcID)
+		name = "IntpcWebsites"
 	}
-	resp, err := parseResponse[[]Website](sdk.apiCall(ctx, http.MethodGet, "/v2/3as/websites", query))
+	op := newOperation(name, http.MethodGet, "/v2/3as/websites").forIntpc(intpcID)
+	resp, err := parseResponse[[]Website](sdk.apiCall(ctx, op, query))
 	if err != nil {
 		return nil, PaginationMetadata{}, err
 	}
@@ -3,21 +3,20 @@ package twipla3as
 import (
 	"context"
 	"net/http"
-	"path"
 )
 
 func (sdk *TwiplaSDK) AddWebsiteWhitelistedDomain(ctx context.Context, websiteID string, domain string) error {
-	_, err := parseResponse[any](sdk.apiCall(ctx, http.MethodPost, path.Join("/v2/3as/websites", websiteID, "whitelisted-domains"), map[string]string{"domain": domain}))
+	_, err := parseResponse[any](sdk.apiCall(ctx, newOperation("AddWebsiteWhitelistedDomain", http.MethodPost, "/v2/3as/websites/{websiteId}/whitelisted-domains", websiteID), map[string]string{"domain": domain}))
 	return err
 }
 
 func (sdk *TwiplaSDK) RemoveWebsiteWhitelistedDomain(ctx context.Context, websiteID string, domain string) error {
-	_, err := parseResponse[any](sdk.apiCall(ctx, http.MethodPatch, path.Join("/v2/3as/websites", websiteID, "whitelisted-domains"), map[string]string{"domain": domain}))
+	_, err := parseResponse[any](sdk.apiCall(ctx, newOperation("RemoveWebsiteWhitelistedDomain", http.MethodPatch, "/v2/3as/websites/{websiteId}/whitelisted-domains", websiteID), map[string]string{"domain": domain}))
 	return err
 }
 
 func (sdk *TwiplaSDK) WhitelistedDomains(ctx context.Context, websiteID string) ([]string, error) {
-	resp, err := parseResponse[[]string](sdk.apiCall(ctx, http.MethodGet, path.Join("/v2/3as/websites", websiteID, "whitelisted-domains"), nil))
+	resp, err := parseResponse[[]string](sdk.apiCall(ctx, newOperation("WhitelistedDomains", http.MethodGet, "/v2/3as/websites/{websiteId}/whitelisted-domains", websiteID), nil))
 	if err != nil {
 		return nil, err
 	}